@@ -0,0 +1,29 @@
+package mcp
+
+// Addressing is device specification layout of request.
+// MELSECコミュニケーションプロトコル リファレンス(p67) MELSEC-Q/L: 3[byte], MELSEC iQ-R: 4[byte]
+type Addressing int
+
+const (
+	// QAddressing is MELSEC-Q/L layout.
+	// device number is 3[byte] and device code is 1[byte]. ascii mode is 6[char] and 2[char].
+	QAddressing Addressing = iota
+
+	// IQRAddressing is MELSEC iQ-R layout. sub command is 0002 (word) or 0003 (bit).
+	// device number is 4[byte] and device code is 2[byte]. ascii mode is 10[char] and 4[char].
+	IQRAddressing
+)
+
+// iqrSubCommands is MELSEC iQ-R sub command for each MELSEC-Q/L sub command. binary mode expression.
+var iqrSubCommands = map[string]string{
+	"0000": "0200", // word units
+	"0100": "0300", // bit units
+}
+
+// subCommand returns sub command of this addressing. subCommand is MELSEC-Q/L sub command.
+func (a Addressing) subCommand(subCommand string) string {
+	if a == IQRAddressing {
+		return iqrSubCommands[subCommand]
+	}
+	return subCommand
+}
@@ -1,182 +1,575 @@
 package mcp
 
 import (
-	"encoding/hex"
+	"context"
 	"errors"
 	"fmt"
-	"net"
+	"strings"
+	"sync/atomic"
 )
 
+// Client is mcp client. It is safe for concurrent use by multiple goroutines.
+// Requests wait in the queue of the client until number of in-flight requests is less than WithMaxInFlight.
+// Typed values and struct binding are provided by Typed over the client, like NewTyped(client).ReadInt32s("D", 100, 2).
+// Methods without ctx like Read, ReadWords and commands of Client3E use context.Background(), so they are limited only by timeout of the client.
+// Use methods with ctx like ReadContext for cancellation, deadline and priority of the request.
 type Client interface {
+	WordReadWriter
 	Read(deviceName string, offset, numPoints int64) ([]byte, error)
+	ReadContext(ctx context.Context, deviceName string, offset, numPoints int64) ([]byte, error)
 	BitRead(deviceName string, offset, numPoints int64) ([]byte, error)
+	BitReadContext(ctx context.Context, deviceName string, offset, numPoints int64) ([]byte, error)
 	Write(deviceName string, offset, numPoints int64, writeData []byte) ([]byte, error)
+	WriteContext(ctx context.Context, deviceName string, offset, numPoints int64, writeData []byte) ([]byte, error)
+	BitWrite(deviceName string, offset int64, values []bool) ([]byte, error)
+	BitWriteContext(ctx context.Context, deviceName string, offset int64, values []bool) ([]byte, error)
+	ReadDevice(device Device, numPoints int64) ([]byte, error)
+	BitReadDevice(device Device, numPoints int64) ([]byte, error)
+	WriteDevice(device Device, numPoints int64, writeData []byte) ([]byte, error)
+	BitWriteDevice(device Device, values []bool) ([]byte, error)
 	HealthCheck() error
+	HealthCheckContext(ctx context.Context) error
+	ConnState() ConnState
+	Close() error
 }
 
-// client3E is 3E frame mcp client
+// Client3E is Client of 3E and 4E frame. It has commands that 1E frame does not support.
+type Client3E interface {
+	Client
+	RandomRead(words, dwords []Device) ([]uint16, []uint32, error)
+	RandomWrite(words []Device, wordValues []uint16, dwords []Device, dwordValues []uint32) error
+	RandomBitWrite(bits []Device, values []bool) error
+	BlockRead(wordBlocks, bitBlocks []Block) ([][]uint16, [][]uint16, error)
+	BlockWrite(wordBlocks []Block, wordData [][]uint16, bitBlocks []Block, bitData [][]uint16) error
+	RegisterMonitor(words, dwords []Device) (*Monitor, error)
+	ReadBuffer(startIO uint16, address, numWords int64) ([]uint16, error)
+	WriteBuffer(startIO uint16, address int64, values []uint16) error
+	RemoteRun(opt RemoteRunOption) error
+	RemoteStop() error
+	RemotePause(force bool) error
+	RemoteLatchClear() error
+	RemoteReset() error
+	ReadCPUModel() (string, uint16, error)
+}
+
+// client3E is 3E frame mcp client.
+// 4E frame client is also client3E because 4E frame is 3E frame with serial number.
 type client3E struct {
-	// PLC address
-	tcpAddr *net.TCPAddr
+	// transport to PLC
+	tr transport
 	// PLC station
 	stn *station
+	// use 4E frame if true
+	frame4E bool
+	// last serial number of 4E frame request
+	serialNum uint32
+	// communication settings and limits of PLC CPU series
+	profile Profile
+	// monitor registration on the connection
+	monitor monitorState
+}
+
+// New3EClient returns 3E frame mcp client.
+// tcp client holds connection between requests, and dials again after the connection is lost. Close the client when it is no longer used.
+func New3EClient(host string, port int, stn *station, opts ...Option) (Client3E, error) {
+	return newClient3E(host, port, stn, false, opts)
 }
 
-func New3EClient(host string, port int, stn *station) (Client, error) {
-	tcpAddr, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("%v:%v", host, port))
+// New4EClient returns 4E frame mcp client.
+// Each request has serial number, and client checks that response has same serial number.
+func New4EClient(host string, port int, stn *station, opts ...Option) (Client3E, error) {
+	return newClient3E(host, port, stn, true, opts)
+}
+
+func newClient3E(host string, port int, stn *station, frame4E bool, opts []Option) (*client3E, error) {
+	o := newOptions(opts)
+	tr, err := newTransport(host, port, o, frameReader3E(o.code, frame4E), MONITORING_TIMER, true, frame4E)
 	if err != nil {
 		return nil, err
 	}
-	return &client3E{tcpAddr: tcpAddr, stn: stn}, nil
+
+	// copy station because options are client settings
+	s := *stn
+	s.code = o.code
+	s.addressing = o.addressing
+	s.series = o.profile.Series
+
+	profile := o.profile
+	profile.Addressing = o.addressing
+
+	c := &client3E{tr: tr, stn: &s, frame4E: frame4E, profile: profile}
+	if o.autoProfile {
+		if err := c.detectProfile(); err != nil {
+			return nil, fmt.Errorf("failed to detect plc profile: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// ConnState returns state of tcp connection held by client. It is always ConnDisconnected in udp.
+func (c *client3E) ConnState() ConnState {
+	return c.tr.state()
+}
+
+// Close closes tcp connection held by client. in-flight requests and requests after Close return ErrClosed.
+func (c *client3E) Close() error {
+	return c.tr.close()
 }
 
 // MELSECコミュニケーションプロトコル p180
 // 11.4折返しテスト
 func (c *client3E) HealthCheck() error {
+	return c.HealthCheckContext(context.Background())
+}
+
+// HealthCheckContext is HealthCheck with ctx. see ReadContext for ctx.
+func (c *client3E) HealthCheckContext(ctx context.Context) error {
 	requestStr := c.stn.BuildHealthCheckRequest()
 
-	// binary protocol
-	payload, err := hex.DecodeString(requestStr)
+	resp, err := c.call(contextWithIdempotent(ctx), requestStr, 30)
 	if err != nil {
 		return err
 	}
 
-	// TODO Keep-Alive
-	conn, err := net.DialTCP("tcp", nil, c.tcpAddr)
+	response, err := NewParser().Do(resp)
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
-
-	// Send message
-	if _, err = conn.Write(payload); err != nil {
-		return err
-	}
 
-	// Receive message
-	readBuff := make([]byte, 30)
-	readLen, err := conn.Read(readBuff)
+	// 折返しデータ数[2byte] + 折返しデータ[5byte]=ABCDE
+	expected, err := c.stn.code.frameBytes(c.stn.code.uintField(int64(len(HEALTH_CHECK_DATA)), 2) + c.stn.code.stringField(HEALTH_CHECK_DATA))
 	if err != nil {
 		return err
 	}
 
-	resp := readBuff[:readLen]
-
-	if readLen != 18 {
+	if len(response.Payload) != len(expected) {
 		return errors.New("plc connect test is fail: return length is [" + fmt.Sprintf("%X", resp) + "]")
 	}
 
-	// decodeString is 折返しデータ数ヘッダ[1byte]
-	if "0500" != fmt.Sprintf("%X", resp[11:13]) {
-		return errors.New("plc connect test is fail: return header is [" + fmt.Sprintf("%X", resp[11:13]) + "]")
+	// decodeString is 折返しデータ数ヘッダ
+	numLen := len(expected) - len(HEALTH_CHECK_DATA)
+	if string(expected[:numLen]) != string(response.Payload[:numLen]) {
+		return errors.New("plc connect test is fail: return header is [" + fmt.Sprintf("%X", response.Payload[:numLen]) + "]")
 	}
 
-	//  折返しデータ[5byte]=ABCDE
-	if "4142434445" != fmt.Sprintf("%X", resp[13:18]) {
-		return errors.New("plc connect test is fail: return body is [" + fmt.Sprintf("%X", resp[13:18]) + "]")
+	//  折返しデータ=ABCDE
+	if HEALTH_CHECK_DATA != string(response.Payload[numLen:]) {
+		return errors.New("plc connect test is fail: return body is [" + fmt.Sprintf("%X", response.Payload[numLen:]) + "]")
 	}
 
 	return nil
 }
 
 // Read is send read as word command to remote plc by mc protocol
-// deviceName is device code name like 'D' register.
+// deviceName is device code name like 'D' register, or extended device specification like J1\W, U3\G or DZ1.
 // offset is device offset addr.
 // numPoints is number of read device points.
+// If numPoints is larger than MaxWordPoints of profile, request is split and response data are joined into one response.
+// If plc returns error end code, the error response and *EndCodeError are returned.
 func (c *client3E) Read(deviceName string, offset, numPoints int64) ([]byte, error) {
-	requestStr := c.stn.BuildReadRequest(deviceName, offset, numPoints)
+	return c.ReadContext(context.Background(), deviceName, offset, numPoints)
+}
 
-	// TODO binary protocol
-	payload, err := hex.DecodeString(requestStr)
+// ReadContext is Read with ctx. If ctx is canceled or its deadline is exceeded while waiting in queue, dial, write or read, ctx.Err() is returned.
+// Without deadline of ctx, response is waited until MONITORING_TIMER of request and RESPONSE_TIMEOUT_MARGIN, and then ErrTimeout is returned.
+// If deadline of ctx is earlier than MONITORING_TIMER, plc may execute the request after error is returned.
+func (c *client3E) ReadContext(ctx context.Context, deviceName string, offset, numPoints int64) ([]byte, error) {
+	resps, err := c.readChunks(ctx, deviceName, offset, numPoints)
 	if err != nil {
-		return nil, err
+		return lastResponse(resps), err
 	}
+	return c.joinResponses(resps)
+}
 
-	conn, err := net.DialTCP("tcp", nil, c.tcpAddr)
+// ReadWords is send read as word command to remote plc by mc protocol, and returns values of numPoints words.
+// If numPoints is larger than MaxWordPoints of profile, request is split.
+// If plc returns error end code, error is returned.
+func (c *client3E) ReadWords(deviceName string, offset, numPoints int64) ([]uint16, error) {
+	resps, err := c.readChunks(context.Background(), deviceName, offset, numPoints)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
-	// Send message
-	if _, err = conn.Write(payload); err != nil {
-		return nil, err
+	// 1 word is 2byte. 4char in ascii
+	width := 2
+	if c.stn.code == Ascii {
+		width = 4
 	}
-
-	// Receive message
-	readBuff := make([]byte, 22+2*numPoints) // 22 is response header size. [sub header + network num + unit i/o num + unit station num + response length + response code]
-	readLen, err := conn.Read(readBuff)
-	if err != nil {
-		return nil, err
+	values := make([]uint16, 0, numPoints)
+	for _, resp := range resps {
+		response, err := c.parse(resp)
+		if err != nil {
+			return nil, err
+		}
+		words, err := c.stn.code.decodeUints(response.Payload, 2, len(response.Payload)/width)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range words {
+			values = append(values, uint16(w))
+		}
+	}
+	if int64(len(values)) != numPoints {
+		return nil, fmt.Errorf("%v words are requested but response is %v words", numPoints, len(values))
 	}
+	return values, nil
+}
 
-	return readBuff[:readLen], nil
+// readChunks sends read as word command for each chunk of MaxWordPoints of profile.
+func (c *client3E) readChunks(ctx context.Context, deviceName string, offset, numPoints int64) ([][]byte, error) {
+	return c.callChunks(contextWithIdempotent(ctx), offset, numPoints, c.profile.MaxWordPoints, func(offset, numPoints int64) (string, int64, error) {
+		requestStr, err := c.stn.BuildReadRequest(deviceName, offset, numPoints)
+
+		// 22 is response header size. [sub header + network num + unit i/o num + unit station num + response length + response code]
+		return requestStr, 22 + 2*numPoints, err
+	})
 }
 
 // BitRead is send read as bit command to remote plc by mc protocol
-// deviceName is device code name like 'D' register.
+// deviceName is device code name like 'D' register, or extended device specification like J1\W, U3\G or DZ1.
 // offset is device offset addr.
 // numPoints is number of read device points.
 // results of payload of BitRead will return []byte contains 0, 1, 16 or 17(hex encoded 00, 01, 10, 11)
+// Use ReadBits to get values as []bool.
+// If numPoints is larger than MaxBitPoints of profile, request is split and response data are joined into one response.
+// If plc returns error end code, the error response and *EndCodeError are returned.
 func (c *client3E) BitRead(deviceName string, offset, numPoints int64) ([]byte, error) {
-	requestStr := c.stn.BuildBitReadRequest(deviceName, offset, numPoints)
-	// TODO binary protocol
-	payload, err := hex.DecodeString(requestStr)
+	return c.BitReadContext(context.Background(), deviceName, offset, numPoints)
+}
+
+// BitReadContext is BitRead with ctx. see ReadContext for ctx.
+func (c *client3E) BitReadContext(ctx context.Context, deviceName string, offset, numPoints int64) ([]byte, error) {
+	resps, err := c.bitReadChunks(ctx, deviceName, offset, numPoints)
 	if err != nil {
-		return nil, err
+		return lastResponse(resps), err
 	}
+	return c.joinResponses(resps)
+}
 
-	conn, err := net.DialTCP("tcp", nil, c.tcpAddr)
+// ReadBits is send read as bit command to remote plc by mc protocol, and returns values of numPoints points.
+// If numPoints is larger than MaxBitPoints of profile, request is split.
+// If plc returns error end code, error is returned.
+func (c *client3E) ReadBits(deviceName string, offset, numPoints int64) ([]bool, error) {
+	resps, err := c.bitReadChunks(context.Background(), deviceName, offset, numPoints)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
-	// Send message
-	if _, err = conn.Write(payload); err != nil {
-		return nil, err
-	}
+	values := make([]bool, 0, numPoints)
+	head := offset
+	for i, resp := range resps {
+		response, err := c.parse(resp)
+		if err != nil {
+			return nil, err
+		}
 
-	// Receive message
-	readBuff := make([]byte, 22+2*numPoints) // 22 is response header size. [sub header + network num + unit i/o num + unit station num + response length + response code]
-	readLen, err := conn.Read(readBuff)
-	if err != nil {
-		return nil, err
+		// number of points of the chunk. all chunks except the last are maxBitPoints
+		n := c.maxBitPoints()
+		if i == len(resps)-1 {
+			n = offset + numPoints - head
+		}
+		bits, err := c.stn.code.decodeBits(response.Payload, int(n))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, bits...)
+		head += n
 	}
+	return values, nil
+}
+
+// bitReadChunks sends read as bit command for each chunk of MaxBitPoints of profile.
+func (c *client3E) bitReadChunks(ctx context.Context, deviceName string, offset, numPoints int64) ([][]byte, error) {
+	return c.callChunks(contextWithIdempotent(ctx), offset, numPoints, c.maxBitPoints(), func(offset, numPoints int64) (string, int64, error) {
+		requestStr, err := c.stn.BuildBitReadRequest(deviceName, offset, numPoints)
 
-	return readBuff[:readLen], nil
+		// 22 is response header size. [sub header + network num + unit i/o num + unit station num + response length + response code]
+		return requestStr, 22 + 2*numPoints, err
+	})
 }
 
 // Write is send write command to remote plc by mc protocol
-// deviceName is device code name like 'D' register.
+// deviceName is device code name like 'D' register, or extended device specification like J1\W, U3\G or DZ1.
 // offset is device offset addr.
 // writeData is data to write.
 // numPoints is number of write device points.
 // writeData is the data to be written. If writeData is larger than 2*numPoints bytes,
 // data larger than 2*numPoints bytes is ignored.
+// If numPoints is larger than MaxWordPoints of profile, request is split and the last response is returned.
+// If plc returns error end code, the error response and *EndCodeError are returned.
 func (c *client3E) Write(deviceName string, offset, numPoints int64, writeData []byte) ([]byte, error) {
-	requestStr := c.stn.BuildWriteRequest(deviceName, offset, numPoints, writeData)
-	payload, err := hex.DecodeString(requestStr)
+	return c.WriteContext(context.Background(), deviceName, offset, numPoints, writeData)
+}
+
+// WriteContext is Write with ctx. see ReadContext for ctx.
+// If ctx is done while split requests, the requests already sent are written.
+func (c *client3E) WriteContext(ctx context.Context, deviceName string, offset, numPoints int64, writeData []byte) ([]byte, error) {
+	if int64(len(writeData)) < 2*numPoints {
+		return nil, fmt.Errorf("write data must be larger than %v byte but actual is %v byte", 2*numPoints, len(writeData))
+	}
+
+	head := offset
+	resps, err := c.callChunks(ctx, offset, numPoints, c.profile.MaxWordPoints, func(offset, numPoints int64) (string, int64, error) {
+		data := writeData[2*(offset-head) : 2*(offset-head+numPoints)]
+		requestStr, err := c.stn.BuildWriteRequest(deviceName, offset, numPoints, data)
+
+		// 22 is response header size. [sub header + network num + unit i/o num + unit station num + response length + response code]
+		return requestStr, 22, err
+	})
+	return lastResponse(resps), err
+}
+
+// WriteWords is send write command to remote plc by mc protocol. values are written from offset.
+// If number of values is larger than MaxWordPoints of profile, request is split.
+// If plc returns error end code, error is returned.
+func (c *client3E) WriteWords(deviceName string, offset int64, values []uint16) error {
+	writeData := make([]byte, 0, 2*len(values))
+	for _, v := range values {
+		writeData = append(writeData, byte(v), byte(v>>8)) // little endian
+	}
+
+	_, err := c.Write(deviceName, offset, int64(len(values)), writeData)
+	return err
+}
+
+// BitWrite is send write as bit command to remote plc by mc protocol
+// deviceName is device code name like 'M' relay, or extended device specification like J1\B.
+// offset is device offset addr.
+// values are written from offset. number of write device points is len(values).
+// If len(values) is larger than MaxBitPoints of profile, request is split and the last response is returned.
+// If plc returns error end code, the error response and *EndCodeError are returned.
+func (c *client3E) BitWrite(deviceName string, offset int64, values []bool) ([]byte, error) {
+	return c.BitWriteContext(context.Background(), deviceName, offset, values)
+}
+
+// BitWriteContext is BitWrite with ctx. see ReadContext for ctx.
+func (c *client3E) BitWriteContext(ctx context.Context, deviceName string, offset int64, values []bool) ([]byte, error) {
+	head := offset
+	resps, err := c.callChunks(ctx, offset, int64(len(values)), c.maxBitPoints(), func(offset, numPoints int64) (string, int64, error) {
+		requestStr, err := c.stn.BuildBitWriteRequest(deviceName, offset, values[offset-head:offset-head+numPoints])
+
+		// 22 is response header size. [sub header + network num + unit i/o num + unit station num + response length + response code]
+		return requestStr, 22, err
+	})
+	return lastResponse(resps), err
+}
+
+// WriteBits is BitWrite without response.
+func (c *client3E) WriteBits(deviceName string, offset int64, values []bool) error {
+	_, err := c.BitWrite(deviceName, offset, values)
+	return err
+}
+
+// parseDevice parses device address by numbering of series of profile.
+func (c *client3E) parseDevice(address string) (Device, error) {
+	return c.profile.ParseDevice(address)
+}
+
+// maxWordPoints returns MaxWordPoints of profile.
+func (c *client3E) maxWordPoints() int64 {
+	return c.profile.MaxWordPoints
+}
+
+// maxBitPoints returns MaxBitPoints of profile rounded to even,
+// so that split bit units response of binary mode can be joined by byte (2 points is 1byte).
+func (c *client3E) maxBitPoints() int64 {
+	if c.profile.MaxBitPoints > 1 {
+		return c.profile.MaxBitPoints &^ 1
+	}
+	return c.profile.MaxBitPoints
+}
+
+// callChunks splits device range into requests that number of points is less than or equal to maxPoints, and calls them in order.
+// build returns request and read size of the chunk from offset to offset+numPoints.
+// If plc returns error end code, following requests are not sent, and the responses until the error and *EndCodeError are returned.
+func (c *client3E) callChunks(ctx context.Context, offset, numPoints, maxPoints int64, build func(offset, numPoints int64) (string, int64, error)) ([][]byte, error) {
+	if numPoints < 1 {
+		return nil, fmt.Errorf("number of points must be larger than 0 but actual is %v", numPoints)
+	}
+	if maxPoints < 1 {
+		return nil, fmt.Errorf("max points of %v must be larger than 0 but actual is %v", c.profile.Series, maxPoints)
+	}
+
+	var resps [][]byte
+	for done := int64(0); done < numPoints; done += maxPoints {
+		n := numPoints - done
+		if n > maxPoints {
+			n = maxPoints
+		}
+
+		requestStr, readSize, err := build(offset+done, n)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.call(ctx, requestStr, readSize)
+		if err != nil {
+			return nil, err
+		}
+		resps = append(resps, resp)
+
+		response, err := NewParser().Do(resp)
+		if err != nil {
+			return nil, err
+		}
+		if err := endCodeError(response, c.stn.code); err != nil {
+			return resps, err
+		}
+	}
+	return resps, nil
+}
+
+// lastResponse returns the last response of resps, or nil if resps is empty.
+func lastResponse(resps [][]byte) []byte {
+	if len(resps) == 0 {
+		return nil
+	}
+	return resps[len(resps)-1]
+}
+
+// joinResponses joins response data of split normal responses into the first response, and rewrites response data length.
+func (c *client3E) joinResponses(resps [][]byte) ([]byte, error) {
+	if len(resps) == 1 {
+		return resps[0], nil
+	}
+
+	var joined []byte
+	for _, resp := range resps {
+		response, err := NewParser().Do(resp)
+		if err != nil {
+			return nil, err
+		}
+		joined = append(joined, response.Payload...)
+	}
+	return c.rewritePayload(resps[0], joined)
+}
+
+// rewritePayload returns normal response that response data of resp is replaced by payload, and rewrites response data length.
+func (c *client3E) rewritePayload(resp []byte, payload []byte) ([]byte, error) {
+	response, err := NewParser().Do(resp)
 	if err != nil {
 		return nil, err
 	}
-	conn, err := net.DialTCP("tcp", nil, c.tcpAddr)
+	headerLen := len(resp) - len(response.Payload)
+	rewritten := append(append([]byte{}, resp[:headerLen]...), payload...)
+
+	// response data length[2byte] and end code[2byte] are just before response data. 4char in ascii
+	fieldLen := 2
+	if c.stn.code == Ascii {
+		fieldLen = 4
+	}
+	dataLen := int64(fieldLen + len(payload))
+	if dataLen > 0xFFFF {
+		return nil, fmt.Errorf("response data length %v exceeds 2byte. read smaller range", dataLen)
+	}
+	dataLenBytes, err := c.stn.code.frameBytes(c.stn.code.uintField(dataLen, 2))
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
+	copy(rewritten[headerLen-2*fieldLen:], dataLenBytes)
+	return rewritten, nil
+}
 
-	// Send message
-	if _, err = conn.Write(payload); err != nil {
+// request sends 3E frame request to remote plc and returns parsed response.
+// If end code of response is not 0000, error is returned.
+// readSize is size of receive buffer.
+func (c *client3E) request(ctx context.Context, requestStr string, readSize int64) (*Response, error) {
+	resp, err := c.call(ctx, requestStr, readSize)
+	if err != nil {
+		return nil, err
+	}
+	return c.parse(resp)
+}
+
+// parse parses raw response. If end code of response is not 0000, *EndCodeError is returned.
+func (c *client3E) parse(resp []byte) (*Response, error) {
+	response, err := NewParser().Do(resp)
+	if err != nil {
+		return nil, err
+	}
+	if err := endCodeError(response, c.stn.code); err != nil {
 		return nil, err
 	}
+	return response, nil
+}
+
+// call sends 3E frame request to remote plc and returns raw response.
+// If client is 4E frame client, request is converted to 4E frame and serial number of response is checked.
+// readSize is size of receive buffer.
+func (c *client3E) call(ctx context.Context, requestStr string, readSize int64) ([]byte, error) {
+	resps, err := c.callAll(ctx, []string{requestStr}, []int64{readSize})
+	if err != nil {
+		return nil, err
+	}
+	return resps[0], nil
+}
 
-	// Receive message
-	readBuff := make([]byte, 22) // 22 is response header size. [sub header + network num + unit i/o num + unit station num + response length + response code]
+// callAll sends 3E frame requests on one connection and returns raw responses in the same order.
+// readSizes are size of receive buffer of each request.
+func (c *client3E) callAll(ctx context.Context, requestStrs []string, readSizes []int64) ([][]byte, error) {
+	payloads := make([][]byte, len(requestStrs))
+	sizes := make([]int64, len(requestStrs))
+	serialNums := make([]uint16, len(requestStrs))
+	for i, requestStr := range requestStrs {
+		sizes[i] = readSizes[i]
+		if c.frame4E {
+			serialNums[i] = uint16(atomic.AddUint32(&c.serialNum, 1))
+			requestStr = c.stn.build4EFrame(requestStr, serialNums[i])
+			sizes[i] += 4 // serial number[2byte] + fixed value[2byte]
+		}
+		if c.stn.code == Ascii {
+			sizes[i] *= 2 // 1byte=2char
+		}
 
-	readLen, err := conn.Read(readBuff)
+		payload, err := c.stn.code.frameBytes(requestStr)
+		if err != nil {
+			return nil, err
+		}
+		payloads[i] = payload
+	}
+
+	resps, err := c.tr.roundTrips(ctx, payloads, sizes)
 	if err != nil {
 		return nil, err
 	}
-	return readBuff[:readLen], nil
+
+	for i, resp := range resps {
+		if err := c.checkResponse(resp, serialNums[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return resps, nil
+}
+
+// checkResponse checks that station and serial number of response frame are the same as the request.
+// serialNum is serial number of 4E frame request.
+func (c *client3E) checkResponse(resp []byte, serialNum uint16) error {
+	var response *Response
+	var err error
+	if c.frame4E {
+		response, err = NewParser().Do4E(resp, serialNum)
+	} else {
+		response, err = NewParser().Do(resp)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFrameMismatch, err)
+	}
+
+	// station fields are binary mode expression
+	fields := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{name: "network number", actual: response.NetworkNum, expected: c.stn.networkNum},
+		{name: "pc number", actual: response.PCNum, expected: c.stn.pcNum},
+		{name: "unit i/o number", actual: response.UnitIONum, expected: c.stn.unitIONum},
+		{name: "unit station number", actual: response.UnitStationNum, expected: c.stn.unitStationNum},
+	}
+	for _, f := range fields {
+		if !strings.EqualFold(f.actual, c.stn.code.layout(f.expected)) {
+			return fmt.Errorf("%w: %v is %v but expected is %v", ErrFrameMismatch, f.name, f.actual, c.stn.code.layout(f.expected))
+		}
+	}
+	return nil
 }
@@ -0,0 +1,345 @@
+package mcp
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// completionCodes1E is completion code and description map of 1E frame.
+var completionCodes1E = map[string]string{
+	"50": "command or response type of sub header is not specified code",
+	"54": "ascii code data that can not be converted to binary is received",
+	"55": "online change is disabled",
+	"56": "device is not specified correctly",
+	"57": "number of points is out of range",
+	"58": "head device number is out of range",
+	"59": "extension file register can not be specified",
+	"5B": "plc cpu and ethernet module can not communicate",
+	"60": "communication time between ethernet module and plc cpu exceeded monitoring timer",
+}
+
+// client1E is 1E frame mcp client
+type client1E struct {
+	// transport to PLC
+	tr transport
+	// PLC station
+	stn *station1E
+}
+
+// New1EClient returns 1E frame mcp client for FX3U-ENET and A compatible modules.
+// 1E frame client supports only binary code.
+func New1EClient(host string, port int, stn *station1E, opts ...Option) (Client, error) {
+	o := newOptions(opts)
+	if o.code != Binary {
+		return nil, errors.New("1E frame client supports only binary code")
+	}
+	tr, err := newTransport(host, port, o, readFrame1E, MONITORING_TIMER_1E, false, false)
+	if err != nil {
+		return nil, err
+	}
+	return &client1E{tr: tr, stn: stn}, nil
+}
+
+// ConnState is always ConnDisconnected because 1E frame client dials for each request.
+func (c *client1E) ConnState() ConnState {
+	return c.tr.state()
+}
+
+// Close does nothing because 1E frame client does not hold connection.
+func (c *client1E) Close() error {
+	return c.tr.close()
+}
+
+// HealthCheck is loopback test of 1E frame.
+func (c *client1E) HealthCheck() error {
+	return c.HealthCheckContext(context.Background())
+}
+
+// HealthCheckContext is HealthCheck with ctx. see ReadContext for ctx.
+func (c *client1E) HealthCheckContext(ctx context.Context) error {
+	requestStr := c.stn.BuildHealthCheckRequest()
+
+	// 2 is response header size. [sub header + completion code] and 1+5 is 折返しデータ数 and 折返しデータ
+	resp, err := c.call(ctx, requestStr, 2+1+5)
+	if err != nil {
+		return err
+	}
+
+	response, err := NewParser().Do1E(resp)
+	if err != nil {
+		return err
+	}
+
+	if len(response.Payload) != 6 {
+		return errors.New("plc connect test is fail: return length is [" + fmt.Sprintf("%X", resp) + "]")
+	}
+
+	// 折返しデータ数[1byte]
+	if "05" != fmt.Sprintf("%X", response.Payload[0:1]) {
+		return errors.New("plc connect test is fail: return header is [" + fmt.Sprintf("%X", response.Payload[0:1]) + "]")
+	}
+
+	//  折返しデータ[5byte]=ABCDE
+	if "4142434445" != fmt.Sprintf("%X", response.Payload[1:6]) {
+		return errors.New("plc connect test is fail: return body is [" + fmt.Sprintf("%X", response.Payload[1:6]) + "]")
+	}
+
+	return nil
+}
+
+// Read is send read as word command to remote plc by 1E frame.
+// deviceName is device code name like 'D' register.
+// offset is device offset addr.
+// numPoints is number of read device points. It must be from 1 to READ_MAX_POINTS_1E.
+func (c *client1E) Read(deviceName string, offset, numPoints int64) ([]byte, error) {
+	return c.ReadContext(context.Background(), deviceName, offset, numPoints)
+}
+
+// ReadContext is Read with ctx. If ctx is canceled or its deadline is exceeded while waiting in queue, dial, write or read, ctx.Err() is returned.
+// Without deadline of ctx, response is waited until MONITORING_TIMER_1E of request and RESPONSE_TIMEOUT_MARGIN, and then ErrTimeout is returned.
+func (c *client1E) ReadContext(ctx context.Context, deviceName string, offset, numPoints int64) ([]byte, error) {
+	if err := validatePoints1E(deviceName, numPoints, READ_MAX_POINTS_1E); err != nil {
+		return nil, err
+	}
+	requestStr := c.stn.BuildReadRequest(deviceName, offset, numPoints)
+
+	// 2 is response header size. [sub header + completion code]
+	return c.call(ctx, requestStr, 2+2*numPoints)
+}
+
+// BitRead is send read as bit command to remote plc by 1E frame.
+// deviceName is device code name like 'M' register.
+// offset is device offset addr.
+// numPoints is number of read device points. It must be from 1 to BIT_READ_MAX_POINTS_1E.
+// results of payload of BitRead will return []byte contains 0, 1, 16 or 17(hex encoded 00, 01, 10, 11)
+// Use ReadBits to get values as []bool.
+func (c *client1E) BitRead(deviceName string, offset, numPoints int64) ([]byte, error) {
+	return c.BitReadContext(context.Background(), deviceName, offset, numPoints)
+}
+
+// BitReadContext is BitRead with ctx. see ReadContext for ctx.
+func (c *client1E) BitReadContext(ctx context.Context, deviceName string, offset, numPoints int64) ([]byte, error) {
+	if err := validatePoints1E(deviceName, numPoints, BIT_READ_MAX_POINTS_1E); err != nil {
+		return nil, err
+	}
+	requestStr := c.stn.BuildBitReadRequest(deviceName, offset, numPoints)
+
+	// 2 is response header size. [sub header + completion code]
+	return c.call(ctx, requestStr, 2+(numPoints+1)/2)
+}
+
+// Write is send write as word command to remote plc by 1E frame.
+// deviceName is device code name like 'D' register.
+// offset is device offset addr.
+// numPoints is number of write device points. It must be from 1 to WRITE_MAX_POINTS_1E.
+// writeData is the data to be written. If writeData is larger than 2*numPoints bytes,
+// data larger than 2*numPoints bytes is ignored.
+func (c *client1E) Write(deviceName string, offset, numPoints int64, writeData []byte) ([]byte, error) {
+	return c.WriteContext(context.Background(), deviceName, offset, numPoints, writeData)
+}
+
+// WriteContext is Write with ctx. see ReadContext for ctx.
+func (c *client1E) WriteContext(ctx context.Context, deviceName string, offset, numPoints int64, writeData []byte) ([]byte, error) {
+	if err := validatePoints1E(deviceName, numPoints, WRITE_MAX_POINTS_1E); err != nil {
+		return nil, err
+	}
+	if int64(len(writeData)) < 2*numPoints {
+		return nil, fmt.Errorf("write data must be larger than %v byte but actual is %v byte", 2*numPoints, len(writeData))
+	}
+	requestStr := c.stn.BuildWriteRequest(deviceName, offset, numPoints, writeData)
+
+	// 2 is response header size. [sub header + completion code]
+	return c.call(ctx, requestStr, 2)
+}
+
+// BitWrite is send write as bit command to remote plc by 1E frame.
+// deviceName is device code name like 'M' register.
+// offset is device offset addr.
+// values are written from offset. number of write device points must be from 1 to BIT_WRITE_MAX_POINTS_1E.
+func (c *client1E) BitWrite(deviceName string, offset int64, values []bool) ([]byte, error) {
+	return c.BitWriteContext(context.Background(), deviceName, offset, values)
+}
+
+// BitWriteContext is BitWrite with ctx. see ReadContext for ctx.
+func (c *client1E) BitWriteContext(ctx context.Context, deviceName string, offset int64, values []bool) ([]byte, error) {
+	if err := validatePoints1E(deviceName, int64(len(values)), BIT_WRITE_MAX_POINTS_1E); err != nil {
+		return nil, err
+	}
+	requestStr := c.stn.BuildBitWriteRequest(deviceName, offset, values)
+
+	// 2 is response header size. [sub header + completion code]
+	return c.call(ctx, requestStr, 2)
+}
+
+// ReadWords is send read as word command to remote plc by 1E frame, and returns values of numPoints words.
+// numPoints must be from 1 to READ_MAX_POINTS_1E.
+func (c *client1E) ReadWords(deviceName string, offset, numPoints int64) ([]uint16, error) {
+	resp, err := c.Read(deviceName, offset, numPoints)
+	if err != nil {
+		return nil, err
+	}
+	response, err := NewParser().Do1E(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	words, err := Binary.decodeUints(response.Payload, 2, int(numPoints))
+	if err != nil {
+		return nil, err
+	}
+	values := make([]uint16, len(words))
+	for i, w := range words {
+		values[i] = uint16(w)
+	}
+	return values, nil
+}
+
+// ReadBits is send read as bit command to remote plc by 1E frame, and returns values of numPoints points.
+// numPoints must be from 1 to BIT_READ_MAX_POINTS_1E.
+func (c *client1E) ReadBits(deviceName string, offset, numPoints int64) ([]bool, error) {
+	resp, err := c.BitRead(deviceName, offset, numPoints)
+	if err != nil {
+		return nil, err
+	}
+	response, err := NewParser().Do1E(resp)
+	if err != nil {
+		return nil, err
+	}
+	return Binary.decodeBits(response.Payload, int(numPoints))
+}
+
+// WriteWords is send write as word command to remote plc by 1E frame. values are written from offset.
+// number of values must be from 1 to WRITE_MAX_POINTS_1E.
+func (c *client1E) WriteWords(deviceName string, offset int64, values []uint16) error {
+	writeData := make([]byte, 0, 2*len(values))
+	for _, v := range values {
+		writeData = append(writeData, byte(v), byte(v>>8)) // little endian
+	}
+	_, err := c.Write(deviceName, offset, int64(len(values)), writeData)
+	return err
+}
+
+// WriteBits is BitWrite of 1E frame without response. error is returned if completion code is not normal.
+func (c *client1E) WriteBits(deviceName string, offset int64, values []bool) error {
+	_, err := c.BitWrite(deviceName, offset, values)
+	return err
+}
+
+// parseDevice parses device address by numbering of MELSEC-F that X and Y are octal.
+func (c *client1E) parseDevice(address string) (Device, error) {
+	return ProfileOf(SeriesFX).ParseDevice(address)
+}
+
+// maxWordPoints returns maximum number of points of 1E frame.
+func (c *client1E) maxWordPoints() int64 {
+	return ProfileOf(SeriesFX).MaxWordPoints
+}
+
+// call sends 1E frame request to remote plc and returns raw response.
+// If completion code of response is not normal, response and error are returned.
+// readSize is size of normal response.
+func (c *client1E) call(ctx context.Context, requestStr string, readSize int64) ([]byte, error) {
+	// binary protocol
+	payload, err := hex.DecodeString(requestStr)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.tr.roundTrip(ctx, payload, readSize)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := NewParser().Do1E(resp)
+	if err != nil {
+		return nil, err
+	}
+	// sub header of response is command of request with the most significant bit
+	if expected := fmt.Sprintf("%02X", payload[0]|0x80); response.SubHeader != expected {
+		return nil, fmt.Errorf("%w: sub header is %v but expected is %v", ErrFrameMismatch, response.SubHeader, expected)
+	}
+	if response.EndCode != END_CODE_1E_NORMAL {
+		msg := "completion code is " + response.EndCode
+		if desc, ok := completionCodes1E[response.EndCode]; ok {
+			msg += ": " + desc
+		}
+		if response.ErrInfo != nil {
+			msg += fmt.Sprintf(" (abnormal code is %X)", response.ErrInfo)
+		}
+		return resp, errors.New(msg)
+	}
+
+	return resp, nil
+}
+
+// validatePoints1E checks device name and number of points of 1E frame request. maxPoints is maximum number of points of the command.
+func validatePoints1E(deviceName string, numPoints, maxPoints int64) error {
+	if _, ok := deviceCodes1E[deviceName]; !ok {
+		return fmt.Errorf("device %v is not supported by 1E frame", deviceName)
+	}
+	if numPoints < 1 || maxPoints < numPoints {
+		return fmt.Errorf("number of points must be from 1 to %v but actual is %v", maxPoints, numPoints)
+	}
+	return nil
+}
+
+// ReadDevice is Read by device address like ParseDevice("D100").
+func (c *client1E) ReadDevice(device Device, numPoints int64) ([]byte, error) {
+	if err := validateWordAccess(device); err != nil {
+		return nil, err
+	}
+	return c.Read(device.Name, device.Offset, numPoints)
+}
+
+// BitReadDevice is BitRead by device address like ParseDevice("M100").
+// bits of word device like D100.F are read through the words, and response is rewritten to response of bit read command.
+func (c *client1E) BitReadDevice(device Device, numPoints int64) ([]byte, error) {
+	if !device.HasBit {
+		return c.BitRead(device.Name, device.Offset, numPoints)
+	}
+
+	numWords := numWordsOfBits(device, numPoints)
+	resp, err := c.Read(device.Name, device.Offset, numWords)
+	if err != nil {
+		return resp, err
+	}
+	response, err := NewParser().Do1E(resp)
+	if err != nil {
+		return nil, err
+	}
+	words, err := Binary.decodeUints(response.Payload, 2, int(numWords))
+	if err != nil {
+		return nil, err
+	}
+	payload, err := Binary.frameBytes(Binary.bitsField(bitsOfWords(words, device.Bit, numPoints)))
+	if err != nil {
+		return nil, err
+	}
+
+	// 2 is response header size. [sub header + completion code]
+	return append(append([]byte{}, resp[:2]...), payload...), nil
+}
+
+// WriteDevice is Write by device address like ParseDevice("D100").
+func (c *client1E) WriteDevice(device Device, numPoints int64, writeData []byte) ([]byte, error) {
+	if err := validateWordAccess(device); err != nil {
+		return nil, err
+	}
+	return c.Write(device.Name, device.Offset, numPoints, writeData)
+}
+
+// BitWriteDevice is BitWrite by device address like ParseDevice("M100").
+// bits of word device like D100.F are written by read-modify-write of the words. see modifyWordBits.
+func (c *client1E) BitWriteDevice(device Device, values []bool) ([]byte, error) {
+	if !device.HasBit {
+		return c.BitWrite(device.Name, device.Offset, values)
+	}
+
+	numWords, writeData, err := modifyWordBits(c, device, values)
+	if err != nil {
+		return nil, err
+	}
+	return c.Write(device.Name, device.Offset, numWords, writeData)
+}
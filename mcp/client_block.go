@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// BlockRead is send multiple block batch read command to remote plc by mc protocol.
+// wordBlocks are word device blocks like D100-D120, and bitBlocks are bit device blocks read as word units like M0-M63.
+// All blocks are read by one request, so that values are the same scan.
+// values are returned for each block in the same order as wordBlocks and bitBlocks.
+func (c *client3E) BlockRead(wordBlocks, bitBlocks []Block) ([][]uint16, [][]uint16, error) {
+	blocks := append(append([]Block{}, wordBlocks...), bitBlocks...)
+	if err := c.validateBlocks(wordBlocks, bitBlocks); err != nil {
+		return nil, nil, err
+	}
+	if err := c.stn.validateBlockReadPoints(blocks); err != nil {
+		return nil, nil, err
+	}
+
+	requestStr, err := c.stn.BuildBlockReadRequest(wordBlocks, bitBlocks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var numPoints int64
+	for _, b := range blocks {
+		numPoints += b.NumPoints
+	}
+
+	// 22 is response header size. 1 point is 2byte
+	response, err := c.request(contextWithIdempotent(context.Background()), requestStr, 22+2*numPoints)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := c.stn.code.decodeUints(response.Payload, 2, int(numPoints))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// split values into each block
+	results := make([][]uint16, len(blocks))
+	for i, b := range blocks {
+		results[i] = make([]uint16, b.NumPoints)
+		for j := range results[i] {
+			results[i][j] = uint16(values[j])
+		}
+		values = values[b.NumPoints:]
+	}
+
+	return results[:len(wordBlocks)], results[len(wordBlocks):], nil
+}
+
+// BlockWrite is send multiple block batch write command to remote plc by mc protocol.
+// wordBlocks are word device blocks written with wordData, and bitBlocks are bit device blocks written as word units with bitData.
+// All blocks are written by one request, so that the PLC is not half written.
+func (c *client3E) BlockWrite(wordBlocks []Block, wordData [][]uint16, bitBlocks []Block, bitData [][]uint16) error {
+	if len(wordBlocks) != len(wordData) {
+		return fmt.Errorf("number of word blocks %v and data %v are mismatched", len(wordBlocks), len(wordData))
+	}
+	if len(bitBlocks) != len(bitData) {
+		return fmt.Errorf("number of bit blocks %v and data %v are mismatched", len(bitBlocks), len(bitData))
+	}
+
+	blocks := append(append([]Block{}, wordBlocks...), bitBlocks...)
+	data := append(append([][]uint16{}, wordData...), bitData...)
+	for i, b := range blocks {
+		if int64(len(data[i])) != b.NumPoints {
+			return fmt.Errorf("number of points of block %v%v is %v but data is %v", b.Name, b.Offset, b.NumPoints, len(data[i]))
+		}
+	}
+	if err := c.validateBlocks(wordBlocks, bitBlocks); err != nil {
+		return err
+	}
+	if err := c.stn.validateBlockWritePoints(blocks); err != nil {
+		return err
+	}
+
+	requestStr, err := c.stn.BuildBlockWriteRequest(wordBlocks, wordData, bitBlocks, bitData)
+	if err != nil {
+		return err
+	}
+
+	// 22 is response header size.
+	_, err = c.request(context.Background(), requestStr, 22)
+	return err
+}
+
+// validateBlocks checks device type and number of points of each block.
+func (c *client3E) validateBlocks(wordBlocks, bitBlocks []Block) error {
+	if err := c.stn.validateBlockDevices(wordBlocks, bitBlocks); err != nil {
+		return err
+	}
+	for _, b := range append(append([]Block{}, wordBlocks...), bitBlocks...) {
+		if b.NumPoints < 1 {
+			return fmt.Errorf("number of points of block %v%v must be larger than 0 but actual is %v", b.Name, b.Offset, b.NumPoints)
+		}
+	}
+	return nil
+}
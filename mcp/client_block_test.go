@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient3E_BlockRead(t *testing.T) {
+	// fake plc returns 1, 2, 3, ... as word values
+	plc := startTCPPLC(t, func(req []byte) []byte {
+		numBlocks := int(req[15]) + int(req[16])
+		data := []byte{}
+		v := 1
+		for i := 0; i < numBlocks; i++ {
+			numPoints := int(req[21+6*i]) | int(req[22+6*i])<<8
+			for j := 0; j < numPoints; j++ {
+				data = append(data, byte(v), 0x00)
+				v++
+			}
+		}
+		resp := []byte{0xD0, 0x00, 0x00, 0xFF, 0xFF, 0x03, 0x00, byte(2 + len(data)), 0x00, 0x00, 0x00}
+		return append(resp, data...)
+	})
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	wordBlocks := []Block{{Name: "D", Offset: 100, NumPoints: 3}, {Name: "W", Offset: 0, NumPoints: 2}}
+	bitBlocks := []Block{{Name: "M", Offset: 0, NumPoints: 4}}
+	wordValues, bitValues, err := client.BlockRead(wordBlocks, bitBlocks)
+	if err != nil {
+		t.Fatalf("unexpected block read err: %v", err)
+	}
+
+	if diff := cmp.Diff(wordValues, [][]uint16{{1, 2, 3}, {4, 5}}); diff != "" {
+		t.Errorf("word values differs: (-got +want)\n%s", diff)
+	}
+	if diff := cmp.Diff(bitValues, [][]uint16{{6, 7, 8, 9}}); diff != "" {
+		t.Errorf("bit values differs: (-got +want)\n%s", diff)
+	}
+}
@@ -0,0 +1,66 @@
+package mcp
+
+import "context"
+
+// ReadBuffer is send intelligent function module buffer memory read command to remote plc by mc protocol.
+// startIO is start I/O number of the module like 0x0030. address is buffer memory address (Un\G address) in word units.
+// numWords is number of read words. If numWords is larger than BUFFER_MAX_WORDS, request is split.
+func (c *client3E) ReadBuffer(startIO uint16, address, numWords int64) ([]uint16, error) {
+	if numWords < 1 {
+		return nil, validateBufferRange(address, numWords)
+	}
+
+	values := make([]uint16, 0, numWords)
+	for done := int64(0); done < numWords; done += BUFFER_MAX_WORDS {
+		n := numWords - done
+		if n > BUFFER_MAX_WORDS {
+			n = BUFFER_MAX_WORDS
+		}
+		if err := validateBufferRange(address+done, n); err != nil {
+			return nil, err
+		}
+
+		requestStr := c.stn.BuildBufferReadRequest(startIO, address+done, n)
+
+		// 22 is response header size. 1 word is 2byte
+		response, err := c.request(contextWithIdempotent(context.Background()), requestStr, 22+2*n)
+		if err != nil {
+			return nil, err
+		}
+		words, err := c.stn.code.decodeUints(response.Payload, 2, int(n))
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range words {
+			values = append(values, uint16(v))
+		}
+	}
+	return values, nil
+}
+
+// WriteBuffer is send intelligent function module buffer memory write command to remote plc by mc protocol.
+// startIO is start I/O number of the module like 0x0030. address is buffer memory address (Un\G address) in word units.
+// values are written from address. If values are larger than BUFFER_MAX_WORDS, request is split.
+func (c *client3E) WriteBuffer(startIO uint16, address int64, values []uint16) error {
+	if len(values) == 0 {
+		return validateBufferRange(address, 0)
+	}
+
+	for done := 0; done < len(values); done += BUFFER_MAX_WORDS {
+		chunk := values[done:]
+		if len(chunk) > BUFFER_MAX_WORDS {
+			chunk = chunk[:BUFFER_MAX_WORDS]
+		}
+		if err := validateBufferRange(address+int64(done), int64(len(chunk))); err != nil {
+			return err
+		}
+
+		requestStr := c.stn.BuildBufferWriteRequest(startIO, address+int64(done), chunk)
+
+		// 22 is response header size.
+		if _, err := c.request(context.Background(), requestStr, 22); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"net"
+	"testing"
+)
+
+func TestClient3E_ReadBuffer(t *testing.T) {
+	numRequests := 0
+
+	// fake plc returns word address as value
+	plc := startTCPPLC(t, func(req []byte) []byte {
+		numRequests++
+		byteAddr := int(req[15]) | int(req[16])<<8 | int(req[17])<<16 | int(req[18])<<24
+		numBytes := int(req[19]) | int(req[20])<<8
+		data := []byte{}
+		for i := 0; i < numBytes/2; i++ {
+			v := byteAddr/2 + i
+			data = append(data, byte(v), byte(v>>8))
+		}
+		resp := []byte{0xD0, 0x00, 0x00, 0xFF, 0xFF, 0x03, 0x00}
+		resp = append(resp, byte(2+len(data)), byte((2+len(data))>>8))
+		resp = append(resp, 0x00, 0x00)
+		return append(resp, data...)
+	})
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	// 1000 words are split into 2 requests
+	values, err := client.ReadBuffer(0x0030, 100, 1000)
+	if err != nil {
+		t.Fatalf("unexpected read buffer err: %v", err)
+	}
+	if numRequests != 2 {
+		t.Fatalf("expected %v but actual is %v", 2, numRequests)
+	}
+	if len(values) != 1000 {
+		t.Fatalf("expected %v but actual is %v", 1000, len(values))
+	}
+	for i, v := range values {
+		if v != uint16(100+i) {
+			t.Fatalf("expected %v but actual is %v", 100+i, v)
+		}
+	}
+}
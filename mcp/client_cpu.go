@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CPU_MODEL_NAME_SIZE is size of cpu model name in response of CPU model name read. padded with space.
+const CPU_MODEL_NAME_SIZE = 16
+
+// ReadCPUModel is send CPU model name read command to remote plc by mc protocol.
+// It returns model name like "Q03UDVCPU" and model code.
+func (c *client3E) ReadCPUModel() (string, uint16, error) {
+	requestStr := c.stn.BuildReadCPUModelRequest()
+
+	// 22 is response header size. model name[16byte] + model code[2byte]
+	response, err := c.request(contextWithIdempotent(context.Background()), requestStr, 22+CPU_MODEL_NAME_SIZE+2)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if len(response.Payload) < CPU_MODEL_NAME_SIZE {
+		return "", 0, fmt.Errorf("response data must be larger than %v byte but actual is %v byte", CPU_MODEL_NAME_SIZE, len(response.Payload))
+	}
+	// model name is characters both in binary and ascii mode
+	name := strings.TrimRight(string(response.Payload[:CPU_MODEL_NAME_SIZE]), " \x00")
+
+	modelCode, err := c.stn.code.decodeUints(response.Payload[CPU_MODEL_NAME_SIZE:], 2, 1)
+	if err != nil {
+		return "", 0, err
+	}
+	return name, uint16(modelCode[0]), nil
+}
+
+// detectProfile reads cpu model name and applies the profile of its series to client.
+func (c *client3E) detectProfile() error {
+	name, _, err := c.ReadCPUModel()
+	if err != nil {
+		return err
+	}
+	profile, err := DetectProfile(name)
+	if err != nil {
+		return err
+	}
+
+	c.profile = profile
+	c.stn.addressing = profile.Addressing
+	c.stn.series = profile.Series
+	return nil
+}
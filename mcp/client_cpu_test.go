@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"net"
+	"testing"
+)
+
+func TestClient3E_ReadCPUModel(t *testing.T) {
+	// fake plc returns "R04CPU" and model code 4800
+	plc := startTCPPLC(t, func(req []byte) []byte {
+		resp := []byte{0xD0, 0x00, 0x00, 0xFF, 0xFF, 0x03, 0x00, 0x14, 0x00, 0x00, 0x00}
+		resp = append(resp, []byte("R04CPU          ")...)
+		return append(resp, 0x00, 0x48)
+	})
+	defer plc.Close()
+
+	port := plc.Addr().(*net.TCPAddr).Port
+	client, err := New3EClient("127.0.0.1", port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	name, modelCode, err := client.ReadCPUModel()
+	if err != nil {
+		t.Fatalf("unexpected read cpu model err: %v", err)
+	}
+	if name != "R04CPU" {
+		t.Fatalf("expected %v but actual is %v", "R04CPU", name)
+	}
+	if modelCode != 0x4800 {
+		t.Fatalf("expected %X but actual is %X", 0x4800, modelCode)
+	}
+
+	// auto profile detects MELSEC iQ-R
+	client2, err := New3EClient("127.0.0.1", port, NewLocalStation(), WithAutoProfile())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	c := client2.(*client3E)
+	if c.profile.Series != SeriesIQR || c.stn.addressing != IQRAddressing {
+		t.Fatalf("expected %v but actual is %v", ProfileOf(SeriesIQR), c.profile)
+	}
+}
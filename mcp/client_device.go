@@ -0,0 +1,109 @@
+package mcp
+
+import "fmt"
+
+// ReadDevice is Read by device address like ParseDevice("D100").
+func (c *client3E) ReadDevice(device Device, numPoints int64) ([]byte, error) {
+	if err := validateWordAccess(device); err != nil {
+		return nil, err
+	}
+	return c.Read(device.Name, device.Offset, numPoints)
+}
+
+// BitReadDevice is BitRead by device address like ParseDevice("X1F").
+// bits of word device like D100.F are read through the words, and response is rewritten to response of bit read command.
+func (c *client3E) BitReadDevice(device Device, numPoints int64) ([]byte, error) {
+	if !device.HasBit {
+		return c.BitRead(device.Name, device.Offset, numPoints)
+	}
+
+	numWords := numWordsOfBits(device, numPoints)
+	resp, err := c.Read(device.Name, device.Offset, numWords)
+	if err != nil {
+		return resp, err
+	}
+	response, err := NewParser().Do(resp)
+	if err != nil {
+		return nil, err
+	}
+	words, err := c.stn.code.decodeUints(response.Payload, 2, int(numWords))
+	if err != nil {
+		return nil, err
+	}
+	payload, err := c.stn.code.frameBytes(c.stn.code.bitsField(bitsOfWords(words, device.Bit, numPoints)))
+	if err != nil {
+		return nil, err
+	}
+	return c.rewritePayload(resp, payload)
+}
+
+// WriteDevice is Write by device address like ParseDevice("D100").
+func (c *client3E) WriteDevice(device Device, numPoints int64, writeData []byte) ([]byte, error) {
+	if err := validateWordAccess(device); err != nil {
+		return nil, err
+	}
+	return c.Write(device.Name, device.Offset, numPoints, writeData)
+}
+
+// BitWriteDevice is BitWrite by device address like ParseDevice("M100").
+// bits of word device like D100.F are written by read-modify-write of the words. see modifyWordBits.
+func (c *client3E) BitWriteDevice(device Device, values []bool) ([]byte, error) {
+	if !device.HasBit {
+		return c.BitWrite(device.Name, device.Offset, values)
+	}
+
+	numWords, writeData, err := modifyWordBits(c, device, values)
+	if err != nil {
+		return nil, err
+	}
+	return c.Write(device.Name, device.Offset, numWords, writeData)
+}
+
+// validateWordAccess returns error if device is bit of word device like D100.F,
+// because batch read and write as word command can not access bit of word device.
+func validateWordAccess(device Device) error {
+	if device.HasBit {
+		return fmt.Errorf("bit of word device %v can not be accessed as word. use bit read and write", device)
+	}
+	return nil
+}
+
+// numWordsOfBits returns number of words that contain numPoints bits from bit of word device like D100.F.
+func numWordsOfBits(device Device, numPoints int64) int64 {
+	return (int64(device.Bit) + numPoints + 15) / 16
+}
+
+// bitsOfWords returns numPoints bits from bit of words. bit 0 is the least significant bit of the first word.
+func bitsOfWords(words []uint64, bit int, numPoints int64) []bool {
+	values := make([]bool, numPoints)
+	for i := range values {
+		n := bit + i
+		values[i] = words[n/16]>>(n%16)&1 == 1
+	}
+	return values
+}
+
+// modifyWordBits reads words that contain bits from bit of word device like D100.F, and returns number of the words and
+// write data that values are set to the bits. Other bits of the words changed by plc between read and write are overwritten.
+func modifyWordBits(rw WordReadWriter, device Device, values []bool) (int64, []byte, error) {
+	numWords := numWordsOfBits(device, int64(len(values)))
+	words, err := rw.ReadWords(device.Name, device.Offset, numWords)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for i, v := range values {
+		n := device.Bit + i
+		if v {
+			words[n/16] |= 1 << (n % 16)
+		} else {
+			words[n/16] &^= 1 << (n % 16)
+		}
+	}
+
+	writeData := make([]byte, 0, 2*len(words))
+	for _, w := range words {
+		writeData = append(writeData, byte(w), byte(w>>8)) // little endian
+	}
+	return numWords, writeData, nil
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient3E_BitOfWordDevice(t *testing.T) {
+	plc := startMemoryPLC(t)
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.WriteWords("D", 100, []uint16{0x1234, 0x5678}); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+
+	// D100.E, D100.F and D101.0 are written by read-modify-write of D100 and D101
+	if _, err := client.BitWriteDevice(MustParseDevice("D100.E"), []bool{true, false, true}); err != nil {
+		t.Fatalf("unexpected bit write err: %v", err)
+	}
+	words, err := client.ReadWords("D", 100, 2)
+	if err != nil {
+		t.Fatalf("unexpected read err: %v", err)
+	}
+	if diff := cmp.Diff([]uint16{0x5234, 0x5679}, words); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+
+	// response is bit read response
+	resp, err := client.BitReadDevice(MustParseDevice("D100.D"), 4)
+	if err != nil {
+		t.Fatalf("unexpected bit read err: %v", err)
+	}
+	response, err := NewParser().Do(resp)
+	if err != nil {
+		t.Fatalf("unexpected parse err: %v", err)
+	}
+	if err := checkDataLen(response, Binary); err != nil {
+		t.Fatalf("unexpected data length err: %v", err)
+	}
+	bits, err := Binary.decodeBits(response.Payload, 4)
+	if err != nil {
+		t.Fatalf("unexpected decode err: %v", err)
+	}
+	if diff := cmp.Diff([]bool{false, true, false, true}, bits); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+
+	if _, err := client.ReadDevice(MustParseDevice("D100.F"), 1); err == nil {
+		t.Fatalf("expected error of word access to bit of word device but actual is nil")
+	}
+}
@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// END_CODE_MONITOR_NOT_REGISTERED is end code of monitor command when devices are not registered on the connection.
+const END_CODE_MONITOR_NOT_REGISTERED = 0xC05D
+
+// Monitor is device set that is registered to remote plc by monitor registration command.
+// Monitor command is cheaper for plc cpu than random read because devices are not sent in each cycle.
+// Registration is kept by the ethernet module only while the connection is alive, and one registration is kept for each connection.
+// So devices are registered on the first read, after the connection is changed or other Monitor of the client is read,
+// and when plc returns END_CODE_MONITOR_NOT_REGISTERED.
+type Monitor struct {
+	// client that the monitor is registered
+	c *client3E
+	// devices monitored as word
+	words []Device
+	// devices monitored as double word
+	dwords []Device
+}
+
+// monitorState is monitor registration kept by the ethernet module for the connection of client.
+type monitorState struct {
+	// mu is held while monitor commands are sent, so that registration of other Monitor is not sent between them
+	mu sync.Mutex
+	// monitor registered on the connection. nil if nothing is registered
+	registered *Monitor
+	// generation of the connection that registered is registered on
+	generation uint64
+}
+
+// RegisterMonitor returns Monitor of words and dwords.
+// words are devices monitored as word, and dwords are devices monitored as double word.
+// Total number of words and dwords must be less than or equal to the limit of random read.
+// Devices are registered to remote plc on the first read.
+func (c *client3E) RegisterMonitor(words, dwords []Device) (*Monitor, error) {
+	if len(words)+len(dwords) == 0 {
+		return nil, errors.New("no device to monitor")
+	}
+	if err := c.stn.validateDevices(append(append([]Device{}, words...), dwords...)); err != nil {
+		return nil, err
+	}
+	if limit := c.stn.RandomReadLimit(); len(words)+len(dwords) > limit {
+		return nil, fmt.Errorf("number of monitor devices must be less than or equal to %v but actual is %v", limit, len(words)+len(dwords))
+	}
+
+	return &Monitor{
+		c:      c,
+		words:  append([]Device{}, words...),
+		dwords: append([]Device{}, dwords...),
+	}, nil
+}
+
+// Read is send monitor command to remote plc by mc protocol.
+// values are returned in the same order as registered words and dwords.
+func (m *Monitor) Read() ([]uint16, []uint32, error) {
+	return m.ReadContext(context.Background())
+}
+
+// ReadContext is Read with ctx. see ReadContext of Client for ctx.
+func (m *Monitor) ReadContext(ctx context.Context) ([]uint16, []uint32, error) {
+	state := &m.c.monitor
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	ctx = contextWithIdempotent(ctx)
+	if state.registered != m || state.generation != m.c.tr.generation() {
+		return m.registerAndRead(ctx)
+	}
+
+	// 22 is response header size. word is 2byte, and double word is 4byte
+	resp, err := m.c.call(ctx, m.c.stn.BuildMonitorRequest(), int64(22+2*len(m.words)+4*len(m.dwords)))
+	if err != nil {
+		return nil, nil, err
+	}
+	response, err := m.c.parse(resp)
+	var endCodeErr *EndCodeError
+	if errors.As(err, &endCodeErr) && endCodeErr.EndCode == END_CODE_MONITOR_NOT_REGISTERED {
+		// registration is lost like reconnection of udp or restart of plc
+		return m.registerAndRead(ctx)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return m.c.decodeRandomValues(response.Payload, len(m.words), len(m.dwords))
+}
+
+// registerAndRead sends monitor registration and monitor command on the same connection. monitorState must be locked.
+func (m *Monitor) registerAndRead(ctx context.Context) ([]uint16, []uint32, error) {
+	state := &m.c.monitor
+	state.registered = nil
+
+	entryStr, err := m.c.stn.BuildEntryMonitorRequest(m.words, m.dwords)
+	if err != nil {
+		return nil, nil, err
+	}
+	requestStrs := []string{
+		entryStr,
+		m.c.stn.BuildMonitorRequest(),
+	}
+	// 22 is response header size. word is 2byte, and double word is 4byte
+	readSizes := []int64{
+		22,
+		int64(22 + 2*len(m.words) + 4*len(m.dwords)),
+	}
+
+	resps, err := m.c.callAll(ctx, requestStrs, readSizes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := m.c.parse(resps[0]); err != nil {
+		return nil, nil, fmt.Errorf("monitor registration is failed: %w", err)
+	}
+	// connection may be changed by other request after the registration. then plc returns END_CODE_MONITOR_NOT_REGISTERED, and it is registered again
+	state.registered = m
+	state.generation = m.c.tr.generation()
+
+	response, err := m.c.parse(resps[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	return m.c.decodeRandomValues(response.Payload, len(m.words), len(m.dwords))
+}
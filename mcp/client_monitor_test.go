@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// monitorPLC is fake plc that keeps monitor registration for each connection like ethernet module.
+// monitor command returns device number of each registered device as value, or END_CODE_MONITOR_NOT_REGISTERED if nothing is registered.
+type monitorPLC struct {
+	net.Listener
+
+	mu sync.Mutex
+	// number of received registrations
+	entries int
+	// registration of each connection is dropped if true
+	forget bool
+}
+
+func startMonitorPLC(t *testing.T) *monitorPLC {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen err: %v", err)
+	}
+	plc := &monitorPLC{Listener: l}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go plc.serve(conn)
+		}
+	}()
+	return plc
+}
+
+func (p *monitorPLC) serve(conn net.Conn) {
+	defer conn.Close()
+	var registered []byte
+	buff := make([]byte, 1024)
+	for {
+		n, err := conn.Read(buff)
+		if err != nil {
+			return
+		}
+		req := buff[:n]
+
+		p.mu.Lock()
+		if p.forget {
+			registered = nil
+			p.forget = false
+		}
+		resp := []byte{0xD0, 0x00, 0x00, 0xFF, 0xFF, 0x03, 0x00}
+		switch {
+		case req[11] == 0x01 && req[12] == 0x08:
+			p.entries++
+			registered = append([]byte{}, req[15:]...)
+			resp = append(resp, 0x02, 0x00, 0x00, 0x00)
+		case req[11] == 0x02 && req[12] == 0x08 && registered != nil:
+			// [number of words, number of double words, devices of 4byte]
+			var data []byte
+			for i := 0; i < int(registered[0]+registered[1]); i++ {
+				offset := registered[2+4*i : 2+4*i+2]
+				data = append(data, offset...)
+				if i >= int(registered[0]) {
+					data = append(data, 0x00, 0x00)
+				}
+			}
+			resp = append(resp, byte(len(data)+2), 0x00, 0x00, 0x00)
+			resp = append(resp, data...)
+		default:
+			resp = append(resp, 0x02, 0x00, 0x5D, 0xC0)
+		}
+		p.mu.Unlock()
+
+		if _, err := conn.Write(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (p *monitorPLC) entryCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.entries
+}
+
+func TestMonitor_Read(t *testing.T) {
+	plc := startMonitorPLC(t)
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	defer client.Close()
+
+	monitor, err := client.RegisterMonitor([]Device{{Name: "D", Offset: 100}}, []Device{{Name: "D", Offset: 200}})
+	if err != nil {
+		t.Fatalf("unexpected register err: %v", err)
+	}
+	other, err := client.RegisterMonitor([]Device{{Name: "D", Offset: 300}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected register err: %v", err)
+	}
+
+	read := func(m *Monitor, expectedWords []uint16, expectedDWords []uint32) {
+		t.Helper()
+		wordValues, dwordValues, err := m.Read()
+		if err != nil {
+			t.Fatalf("unexpected monitor err: %v", err)
+		}
+		if diff := cmp.Diff(wordValues, expectedWords); diff != "" {
+			t.Errorf("word values differs: (-got +want)\n%s", diff)
+		}
+		if diff := cmp.Diff(dwordValues, expectedDWords); diff != "" {
+			t.Errorf("double word values differs: (-got +want)\n%s", diff)
+		}
+	}
+
+	cases := []struct {
+		name    string
+		before  func()
+		monitor *Monitor
+		entries int
+	}{
+		{name: "first read registers", monitor: monitor, entries: 1},
+		{name: "registration is kept on the connection", monitor: monitor, entries: 1},
+		{name: "other monitor registers", monitor: other, entries: 2},
+		{name: "registration of other monitor is replaced", monitor: monitor, entries: 3},
+		{name: "new connection", before: client.(*client3E).tr.disconnect, monitor: monitor, entries: 4},
+		{name: "registration is kept on new connection", monitor: monitor, entries: 4},
+		{
+			name: "registration is lost by plc",
+			before: func() {
+				plc.mu.Lock()
+				plc.forget = true
+				plc.mu.Unlock()
+			},
+			monitor: monitor,
+			entries: 5,
+		},
+	}
+
+	for _, v := range cases {
+		if v.before != nil {
+			v.before()
+		}
+		if v.monitor == monitor {
+			read(monitor, []uint16{100}, []uint32{200})
+		} else {
+			read(other, []uint16{300}, []uint32{})
+		}
+		if entries := plc.entryCount(); entries != v.entries {
+			t.Fatalf("%v: expected %v but actual is %v", v.name, v.entries, entries)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// RandomRead is send random read command to remote plc by mc protocol.
+// words are devices read as word, and dwords are devices read as double word.
+// values are returned in the same order as words and dwords.
+// If total number of words and dwords exceeds the limit of one request, they are split into several requests.
+func (c *client3E) RandomRead(words, dwords []Device) ([]uint16, []uint32, error) {
+	if err := c.stn.validateDevices(append(append([]Device{}, words...), dwords...)); err != nil {
+		return nil, nil, err
+	}
+
+	wordValues := make([]uint16, 0, len(words))
+	dwordValues := make([]uint32, 0, len(dwords))
+
+	limit := c.stn.RandomReadLimit()
+	for len(words) > 0 || len(dwords) > 0 {
+		// words are packed first, and dwords are packed in the rest of the request
+		numWords := len(words)
+		if numWords > limit {
+			numWords = limit
+		}
+		numDWords := len(dwords)
+		if numDWords > limit-numWords {
+			numDWords = limit - numWords
+		}
+
+		requestStr, err := c.stn.BuildRandomReadRequest(words[:numWords], dwords[:numDWords])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// 22 is response header size. word is 2byte, and double word is 4byte
+		response, err := c.request(contextWithIdempotent(context.Background()), requestStr, int64(22+2*numWords+4*numDWords))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		w, d, err := c.decodeRandomValues(response.Payload, numWords, numDWords)
+		if err != nil {
+			return nil, nil, err
+		}
+		wordValues = append(wordValues, w...)
+		dwordValues = append(dwordValues, d...)
+
+		words = words[numWords:]
+		dwords = dwords[numDWords:]
+	}
+
+	return wordValues, dwordValues, nil
+}
+
+// decodeRandomValues decodes response data of random read and monitor.
+// word data[2byte] of numWords is followed by double word data[4byte] of numDWords.
+func (c *client3E) decodeRandomValues(data []byte, numWords, numDWords int) ([]uint16, []uint32, error) {
+	values, err := c.stn.code.decodeUints(data, 2, numWords)
+	if err != nil {
+		return nil, nil, err
+	}
+	wordValues := make([]uint16, numWords)
+	for i, v := range values {
+		wordValues[i] = uint16(v)
+	}
+
+	// double word data follows word data
+	wordsLen := 2 * numWords
+	if c.stn.code == Ascii {
+		wordsLen *= 2
+	}
+	values, err = c.stn.code.decodeUints(data[wordsLen:], 4, numDWords)
+	if err != nil {
+		return nil, nil, err
+	}
+	dwordValues := make([]uint32, numDWords)
+	for i, v := range values {
+		dwordValues[i] = uint32(v)
+	}
+
+	return wordValues, dwordValues, nil
+}
+
+// RandomWrite is send random write as word command to remote plc by mc protocol.
+// words are devices written as word with wordValues, and dwords are devices written as double word with dwordValues.
+// All values are written by one request, so that the PLC is not half written.
+// If number of points exceeds the limit of one request, error is returned without writing.
+func (c *client3E) RandomWrite(words []Device, wordValues []uint16, dwords []Device, dwordValues []uint32) error {
+	if len(words) != len(wordValues) {
+		return fmt.Errorf("number of words %v and values %v are mismatched", len(words), len(wordValues))
+	}
+	if len(dwords) != len(dwordValues) {
+		return fmt.Errorf("number of double words %v and values %v are mismatched", len(dwords), len(dwordValues))
+	}
+	if err := c.stn.validateDevices(append(append([]Device{}, words...), dwords...)); err != nil {
+		return err
+	}
+	if err := c.stn.validateRandomWritePoints(len(words), len(dwords)); err != nil {
+		return err
+	}
+
+	requestStr, err := c.stn.BuildRandomWriteRequest(words, wordValues, dwords, dwordValues)
+	if err != nil {
+		return err
+	}
+
+	// 22 is response header size.
+	_, err = c.request(context.Background(), requestStr, 22)
+	return err
+}
+
+// RandomBitWrite is send random write as bit command to remote plc by mc protocol.
+// bits are devices written as bit with values.
+// All values are written by one request, so that the PLC is not half written.
+// If number of points exceeds the limit of one request, error is returned without writing.
+func (c *client3E) RandomBitWrite(bits []Device, values []bool) error {
+	if len(bits) != len(values) {
+		return fmt.Errorf("number of bits %v and values %v are mismatched", len(bits), len(values))
+	}
+	if err := c.stn.validateDevices(bits); err != nil {
+		return err
+	}
+	if err := c.stn.validateRandomBitWritePoints(len(bits)); err != nil {
+		return err
+	}
+
+	requestStr, err := c.stn.BuildRandomBitWriteRequest(bits, values)
+	if err != nil {
+		return err
+	}
+
+	// 22 is response header size.
+	_, err = c.request(context.Background(), requestStr, 22)
+	return err
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"net"
+	"testing"
+)
+
+func TestClient3E_RandomRead(t *testing.T) {
+	numRequests := 0
+
+	// fake plc returns device offset as word value and offset+0x10000 as double word value
+	plc := startTCPPLC(t, func(req []byte) []byte {
+		numRequests++
+		numWords, numDWords := int(req[15]), int(req[16])
+		data := []byte{}
+		for i := 0; i < numWords+numDWords; i++ {
+			dev := req[17+4*i : 21+4*i]
+			offset := uint32(dev[0]) | uint32(dev[1])<<8 | uint32(dev[2])<<16
+			if i < numWords {
+				data = append(data, byte(offset), byte(offset>>8))
+			} else {
+				v := offset + 0x10000
+				data = append(data, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+			}
+		}
+		resp := []byte{0xD0, 0x00, 0x00, 0xFF, 0xFF, 0x03, 0x00}
+		resp = append(resp, byte(2+len(data)), byte((2+len(data))>>8))
+		resp = append(resp, 0x00, 0x00)
+		return append(resp, data...)
+	})
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	// 200 words and 10 dwords are split into 2 requests
+	words := make([]Device, 200)
+	for i := range words {
+		words[i] = Device{Name: "D", Offset: int64(i)}
+	}
+	dwords := make([]Device, 10)
+	for i := range dwords {
+		dwords[i] = Device{Name: "ZR", Offset: int64(1000 + i)}
+	}
+
+	wordValues, dwordValues, err := client.RandomRead(words, dwords)
+	if err != nil {
+		t.Fatalf("unexpected random read err: %v", err)
+	}
+	if numRequests != 2 {
+		t.Fatalf("expected %v but actual is %v", 2, numRequests)
+	}
+	if len(wordValues) != 200 || len(dwordValues) != 10 {
+		t.Fatalf("expected %v and %v values but actual is %v and %v", 200, 10, len(wordValues), len(dwordValues))
+	}
+	for i, v := range wordValues {
+		if v != uint16(i) {
+			t.Fatalf("expected %v but actual is %v", i, v)
+		}
+	}
+	for i, v := range dwordValues {
+		if v != uint32(0x10000+1000+i) {
+			t.Fatalf("expected %v but actual is %v", 0x10000+1000+i, v)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// REMOTE_RESET_TIMEOUT is time to wait response of remote RESET. plc may be reset before the response is sent.
+const REMOTE_RESET_TIMEOUT = 2 * time.Second
+
+var (
+	// ErrRemotePasswordLocked is reason of refusal when the port of ethernet module is locked by remote password.
+	ErrRemotePasswordLocked = errors.New("mcp: locked by remote password")
+	// ErrCPUState is reason of refusal when plc cpu is not in the state that the operation can be executed.
+	ErrCPUState = errors.New("mcp: wrong cpu state")
+)
+
+// remoteRefusalReasons is end code and reason map of refused remote operation.
+var remoteRefusalReasons = map[uint16]error{
+	0xC200: ErrRemotePasswordLocked, // remote password is mismatched
+	0xC201: ErrRemotePasswordLocked, // port is locked by remote password
+	0xC204: ErrRemotePasswordLocked, // port is unlocked by other device
+	0x4010: ErrCPUState,             // plc cpu is running
+	0x4013: ErrCPUState,             // plc cpu is running
+	0x408B: ErrCPUState,             // remote request can not be executed in current state
+}
+
+// RemoteOperationError is returned when remote operation is refused by plc.
+// Use errors.Is with ErrRemotePasswordLocked or ErrCPUState to check the reason,
+// and errors.As with *EndCodeError to get error information of the response.
+type RemoteOperationError struct {
+	// Operation is name of remote operation like RUN.
+	Operation string
+	// EndCode is end code of response.
+	EndCode uint16
+	// Err is reason of refusal. It is nil if reason is unknown.
+	Err error
+	// EndCodeErr is end code and error information of the response.
+	EndCodeErr *EndCodeError
+}
+
+func (e *RemoteOperationError) Error() string {
+	msg := fmt.Sprintf("remote %v is refused by plc: end code is %04X", e.Operation, e.EndCode)
+	if e.EndCodeErr != nil {
+		msg = fmt.Sprintf("remote %v is refused by plc: %v", e.Operation, e.EndCodeErr)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns EndCodeErr, so that errors.As finds *EndCodeError.
+func (e *RemoteOperationError) Unwrap() error {
+	if e.EndCodeErr == nil {
+		return nil
+	}
+	return e.EndCodeErr
+}
+
+// Is reports whether target is the reason of refusal.
+func (e *RemoteOperationError) Is(target error) bool {
+	return e.Err != nil && errors.Is(e.Err, target)
+}
+
+// RemoteRun is send remote RUN command to remote plc by mc protocol.
+func (c *client3E) RemoteRun(opt RemoteRunOption) error {
+	return c.remoteOperation(context.Background(), "RUN", c.stn.BuildRemoteRunRequest(opt))
+}
+
+// RemoteStop is send remote STOP command to remote plc by mc protocol.
+func (c *client3E) RemoteStop() error {
+	return c.remoteOperation(context.Background(), "STOP", c.stn.BuildRemoteStopRequest())
+}
+
+// RemotePause is send remote PAUSE command to remote plc by mc protocol.
+// If force is true, remote PAUSE is executed even if other device executes remote STOP.
+func (c *client3E) RemotePause(force bool) error {
+	return c.remoteOperation(context.Background(), "PAUSE", c.stn.BuildRemotePauseRequest(force))
+}
+
+// RemoteLatchClear is send remote latch clear command to remote plc by mc protocol. plc cpu must be STOP.
+func (c *client3E) RemoteLatchClear() error {
+	return c.remoteOperation(context.Background(), "latch clear", c.stn.BuildRemoteLatchClearRequest())
+}
+
+// RemoteReset is send remote RESET command to remote plc by mc protocol. plc cpu must be STOP.
+// Response may not be returned because the plc is reset, so closed connection or no response within REMOTE_RESET_TIMEOUT
+// after the request is sent is success. connection is closed after RESET, and next request dials again.
+func (c *client3E) RemoteReset() error {
+	ctx, cancel := context.WithTimeout(context.Background(), REMOTE_RESET_TIMEOUT)
+	defer cancel()
+	// connection is lost by reset of the ethernet module
+	defer c.tr.disconnect()
+
+	err := c.remoteOperation(ctx, "RESET", c.stn.BuildRemoteResetRequest())
+	if isNoResponse(err) {
+		return nil
+	}
+	return err
+}
+
+// remoteOperation sends remote operation request, and returns RemoteOperationError if plc refuses it.
+func (c *client3E) remoteOperation(ctx context.Context, operation, requestStr string) error {
+	// 22 is response header size. error information[9byte] follows end code if refused
+	resp, err := c.call(ctx, requestStr, 22+9)
+	if err != nil {
+		return err
+	}
+
+	response, err := NewParser().Do(resp)
+	if err != nil {
+		return err
+	}
+	if err := endCodeError(response, c.stn.code); err != nil {
+		var endCodeErr *EndCodeError
+		if !errors.As(err, &endCodeErr) {
+			return err
+		}
+		return &RemoteOperationError{
+			Operation:  operation,
+			EndCode:    endCodeErr.EndCode,
+			Err:        remoteRefusalReasons[endCodeErr.EndCode],
+			EndCodeErr: endCodeErr,
+		}
+	}
+	return nil
+}
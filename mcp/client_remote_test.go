@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient3E_RemoteRun(t *testing.T) {
+	plc := startTCPPLC(t, func(req []byte) []byte {
+		return []byte{0xD0, 0x00, 0x00, 0xFF, 0xFF, 0x03, 0x00, 0x02, 0x00, 0x00, 0x00}
+	})
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	if err := client.RemoteRun(RemoteRunOption{Force: true}); err != nil {
+		t.Fatalf("unexpected remote run err: %v", err)
+	}
+}
+
+func TestClient3E_RemoteStopRefused(t *testing.T) {
+	// end code C201 and error information
+	plc := startTCPPLC(t, func(req []byte) []byte {
+		return []byte{0xD0, 0x00, 0x00, 0xFF, 0xFF, 0x03, 0x00, 0x0B, 0x00, 0x01, 0xC2,
+			0x00, 0xFF, 0xFF, 0x03, 0x00, 0x02, 0x10, 0x00, 0x00}
+	})
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	err = client.RemoteStop()
+	if !errors.Is(err, ErrRemotePasswordLocked) {
+		t.Fatalf("expected %v but actual is %v", ErrRemotePasswordLocked, err)
+	}
+	var opErr *RemoteOperationError
+	if !errors.As(err, &opErr) || opErr.EndCode != 0xC201 {
+		t.Fatalf("expected end code %X but actual is %v", 0xC201, err)
+	}
+	var endCodeErr *EndCodeError
+	if !errors.As(err, &endCodeErr) {
+		t.Fatalf("expected *EndCodeError but actual is %v", err)
+	}
+	expected := &ErrInfo{NetworkNum: 0x00, PCNum: 0xFF, UnitIONum: 0x03FF, UnitStationNum: 0x00, Command: 0x1002, SubCommand: 0x0000}
+	if diff := cmp.Diff(expected, endCodeErr.ErrInfo); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+}
+
+func TestClient3E_RemoteResetNoResponse(t *testing.T) {
+	// plc is reset and closes connection without response
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen err: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			buff := make([]byte, UDP_READ_BUFFER_SIZE)
+			_, _ = conn.Read(buff)
+			conn.Close()
+		}
+	}()
+
+	client, err := New3EClient("127.0.0.1", l.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	defer client.Close()
+
+	start := time.Now()
+	if err := client.RemoteReset(); err != nil {
+		t.Fatalf("unexpected remote reset err: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= REMOTE_RESET_TIMEOUT {
+		t.Fatalf("expected closed connection is detected but actual is %v", elapsed)
+	}
+	if client.ConnState() != ConnDisconnected {
+		t.Fatalf("expected %v but actual is %v", ConnDisconnected, client.ConnState())
+	}
+}
+
+func TestClient3E_RemoteResetDialFailure(t *testing.T) {
+	// closed port
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen err: %v", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	client, err := New3EClient("127.0.0.1", port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	defer client.Close()
+
+	// request is not sent
+	if err := client.RemoteReset(); err == nil {
+		t.Fatalf("expected dial error but actual is nil")
+	}
+}
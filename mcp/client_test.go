@@ -2,15 +2,20 @@ package mcp
 
 import (
 	"encoding/hex"
+	"net"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 var (
-	testPLCHost string
-	testPLCPort int
+	testPLCHost   string
+	testPLCPort   int
+	testPLC1EPort int
 )
 
 func init() {
@@ -20,6 +25,11 @@ func init() {
 			testPLCPort = port
 		}
 	}
+	if p := os.Getenv("PLC_TEST_1E_PORT"); p != "" {
+		if port, err := strconv.Atoi(p); err == nil {
+			testPLC1EPort = port
+		}
+	}
 }
 
 func TestClient3E_Read(t *testing.T) {
@@ -159,3 +169,251 @@ func TestClient3E_Ping(t *testing.T) {
 		t.Fatalf("unexpected error occured %v", err)
 	}
 }
+
+func TestClient4E_Ping(t *testing.T) {
+	// running only when there is and plc that can be accepted mc protocol
+	if testPLCHost == "" {
+		t.Skip("environment variable PLC_TEST_HOST is not set")
+	}
+	if testPLCPort == 0 {
+		t.Skip("environment variable PLC_TEST_PORT is not set")
+	}
+
+	client, err := New4EClient(testPLCHost, testPLCPort, NewLocalStation())
+	if err != nil {
+		t.Fatalf("PLC does not exists? %v", err)
+	}
+
+	if err := client.HealthCheck(); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	if _, err := client.Read("D", 100, 1); err != nil {
+		t.Fatalf("unexpected mcp read err: %v", err)
+	}
+}
+
+func TestClient1E_Ping(t *testing.T) {
+	// running only when there is and plc that can be accepted 1E frame mc protocol
+	if testPLCHost == "" {
+		t.Skip("environment variable PLC_TEST_HOST is not set")
+	}
+	if testPLC1EPort == 0 {
+		t.Skip("environment variable PLC_TEST_1E_PORT is not set")
+	}
+
+	client, err := New1EClient(testPLCHost, testPLC1EPort, NewLocalStation1E())
+	if err != nil {
+		t.Fatalf("PLC does not exists? %v", err)
+	}
+
+	if err := client.HealthCheck(); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+
+	resp, err := client.Read("D", 100, 2)
+	if err != nil {
+		t.Fatalf("unexpected mcp read err: %v", err)
+	}
+	if len(resp) != 6 {
+		t.Fatalf("expected %v but actual is %v", 6, len(resp))
+	}
+}
+
+func TestClient1E_MaxPoints(t *testing.T) {
+	// requests over the limit are rejected before sending
+	client, err := New1EClient("127.0.0.1", 5000, NewLocalStation1E())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	if _, err := client.BitWrite("M", 0, make([]bool, BIT_WRITE_MAX_POINTS_1E+1)); err == nil {
+		t.Errorf("expected error of bit write over %v points but actual is nil", BIT_WRITE_MAX_POINTS_1E)
+	}
+	if _, err := client.Read("D", 0, READ_MAX_POINTS_1E+1); err == nil {
+		t.Errorf("expected error of read over %v points but actual is nil", READ_MAX_POINTS_1E)
+	}
+	if _, err := client.BitRead("M", 0, BIT_READ_MAX_POINTS_1E+1); err == nil {
+		t.Errorf("expected error of bit read over %v points but actual is nil", BIT_READ_MAX_POINTS_1E)
+	}
+	if _, err := client.Write("D", 0, WRITE_MAX_POINTS_1E+1, make([]byte, 2*(WRITE_MAX_POINTS_1E+1))); err == nil {
+		t.Errorf("expected error of write over %v points but actual is nil", WRITE_MAX_POINTS_1E)
+	}
+}
+
+// memoryPLC is fake plc that has word and bit device memory, and responds binary 3E frame batch read and write.
+type memoryPLC struct {
+	net.Listener
+	mu sync.Mutex
+	// words is word units memory of each device code
+	words map[byte][]uint16
+	// numRequests is number of received requests
+	numRequests int
+}
+
+// startMemoryPLC starts memoryPLC.
+func startMemoryPLC(t *testing.T) *memoryPLC {
+	plc := &memoryPLC{words: map[byte][]uint16{}}
+	plc.Listener = startTCPPLC(t, plc.handle)
+	return plc
+}
+
+// memory returns word units memory of device code. bit device is 16 points per word.
+func (p *memoryPLC) memory(deviceCode byte) []uint16 {
+	if _, ok := p.words[deviceCode]; !ok {
+		p.words[deviceCode] = make([]uint16, 0x10000)
+	}
+	return p.words[deviceCode]
+}
+
+func (p *memoryPLC) requests() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.numRequests
+}
+
+func (p *memoryPLC) handle(req []byte) []byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.numRequests++
+
+	command := int(req[11]) | int(req[12])<<8
+	bitUnits := req[13] == 0x01
+	offset := int(req[15]) | int(req[16])<<8 | int(req[17])<<16
+	mem := p.memory(req[18])
+	numPoints := int(req[19]) | int(req[20])<<8
+
+	bit := func(i int) byte { return byte(mem[i/16] >> (i % 16) & 1) }
+	setBit := func(i int, v byte) {
+		mem[i/16] = mem[i/16]&^(1<<(i%16)) | uint16(v)<<(i%16)
+	}
+
+	data := []byte{}
+	switch {
+	case command == 0x0401 && bitUnits:
+		for i := 0; i < numPoints; i += 2 {
+			b := bit(offset+i) << 4
+			if i+1 < numPoints {
+				b |= bit(offset + i + 1)
+			}
+			data = append(data, b)
+		}
+	case command == 0x0401:
+		for i := 0; i < numPoints; i++ {
+			data = append(data, byte(mem[offset+i]), byte(mem[offset+i]>>8))
+		}
+	case command == 0x1401 && bitUnits:
+		for i := 0; i < numPoints; i++ {
+			setBit(offset+i, req[21+i/2]>>(4*(1-i%2))&1)
+		}
+	case command == 0x1401:
+		for i := 0; i < numPoints; i++ {
+			mem[offset+i] = uint16(req[21+2*i]) | uint16(req[22+2*i])<<8
+		}
+	}
+
+	resp := []byte{0xD0, 0x00, 0x00, 0xFF, 0xFF, 0x03, 0x00}
+	resp = append(resp, byte(2+len(data)), byte((2+len(data))>>8))
+	resp = append(resp, 0x00, 0x00)
+	return append(resp, data...)
+}
+
+func TestClient3E_ReadSplit(t *testing.T) {
+	plc := startMemoryPLC(t)
+	defer plc.Close()
+
+	profile := ProfileOf(SeriesQ)
+	profile.MaxWordPoints = 10
+	profile.MaxBitPoints = 7
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation(), WithProfile(profile))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	// 25 words are written by 3 requests
+	writeData := make([]byte, 50)
+	for i := range writeData {
+		writeData[i] = byte(i)
+	}
+	if _, err := client.Write("D", 100, 25, writeData); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+	if plc.requests() != 3 {
+		t.Fatalf("expected %v but actual is %v", 3, plc.requests())
+	}
+
+	// 25 words are read by 3 requests and joined into one response
+	resp, err := client.Read("D", 100, 25)
+	if err != nil {
+		t.Fatalf("unexpected read err: %v", err)
+	}
+	if plc.requests() != 6 {
+		t.Fatalf("expected %v but actual is %v", 6, plc.requests())
+	}
+	response, err := NewParser().Do(resp)
+	if err != nil {
+		t.Fatalf("unexpected parse err: %v", err)
+	}
+	if err := checkDataLen(response, Binary); err != nil {
+		t.Fatalf("unexpected data length err: %v", err)
+	}
+	if hex.EncodeToString(response.Payload) != hex.EncodeToString(writeData) {
+		t.Fatalf("expected %X but actual is %X", writeData, response.Payload)
+	}
+
+	// 15 bits are split by 6 points because max bit points is rounded to even
+	values := make([]bool, 15)
+	for i := range values {
+		values[i] = i%3 == 0
+	}
+	if _, err := client.BitWrite("M", 3, values); err != nil {
+		t.Fatalf("unexpected bit write err: %v", err)
+	}
+	bitResp, err := client.BitRead("M", 3, 15)
+	if err != nil {
+		t.Fatalf("unexpected bit read err: %v", err)
+	}
+	bitResponse, _ := NewParser().Do(bitResp)
+	if hex.EncodeToString(bitResponse.Payload) != "1001001001001000" {
+		t.Fatalf("expected %v but actual is %X", "1001001001001000", bitResponse.Payload)
+	}
+}
+
+func TestClient3E_ReadBits(t *testing.T) {
+	plc := startMemoryPLC(t)
+	defer plc.Close()
+
+	profile := ProfileOf(SeriesQ)
+	profile.MaxBitPoints = 6
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation(), WithProfile(profile))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	values := []bool{true, false, true, true, false, false, true, false, false, true, true}
+	if _, err := client.BitWrite("M", 10, values); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+
+	// 11 points are read by 2 requests of 6 and 5 points
+	before := plc.requests()
+	actual, err := client.ReadBits("M", 10, 11)
+	if err != nil {
+		t.Fatalf("unexpected read err: %v", err)
+	}
+	if plc.requests()-before != 2 {
+		t.Fatalf("expected %v but actual is %v", 2, plc.requests()-before)
+	}
+	if diff := cmp.Diff(values, actual); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+
+	// odd number of points
+	actual, err = client.ReadBits("M", 10, 3)
+	if err != nil {
+		t.Fatalf("unexpected read err: %v", err)
+	}
+	if diff := cmp.Diff(values[:3], actual); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/hex"
+	"fmt"
+	"strconv"
 )
 
 // PLC Data communication code.
@@ -20,6 +22,7 @@ const (
 	Binary
 )
 
+// EncodeHex encodes hex string that is stored from upper byte to lower byte like "0401".
 func (c Code) EncodeHex(s string) ([]byte, error) {
 	if c == Ascii {
 		return []byte(s), nil
@@ -31,6 +34,154 @@ func (c Code) EncodeHex(s string) ([]byte, error) {
 	}
 
 	buff := new(bytes.Buffer)
-	_ = binary.Write(buff, binary.LittleEndian, decode)
+	for i := len(decode) - 1; i >= 0; i-- {
+		buff.WriteByte(decode[i])
+	}
 	return buff.Bytes(), nil
 }
+
+// layout converts binary mode expression like "0104" to the expression of this code.
+// binary mode expression is stored from lower byte to upper byte, so ascii mode expression is reversed by byte like "0401".
+func (c Code) layout(binaryHex string) string {
+	if c == Binary {
+		return binaryHex
+	}
+
+	s := ""
+	for i := len(binaryHex); i >= 2; i -= 2 {
+		s += binaryHex[i-2 : i]
+	}
+	return s
+}
+
+// uintField returns v as size byte field of request.
+// binary mode is little endian hex like "2C0100", ascii mode is big endian hex like "00012C".
+func (c Code) uintField(v int64, size int) string {
+	buff := new(bytes.Buffer)
+	_ = binary.Write(buff, binary.LittleEndian, v)
+	return c.layout(fmt.Sprintf("%X", buff.Bytes()[0:size]))
+}
+
+// stringField returns s as characters field of request.
+// binary mode is hex of characters like "4142", ascii mode is characters itself like "AB".
+func (c Code) stringField(s string) string {
+	if c == Ascii {
+		return s
+	}
+	return fmt.Sprintf("%X", s)
+}
+
+// wordsField returns data as word units field of request. data is little endian word.
+// ascii mode expresses 1 word as 4 characters from upper byte to lower byte.
+func (c Code) wordsField(data []byte) string {
+	if c == Binary {
+		return fmt.Sprintf("%X", data)
+	}
+
+	s := ""
+	for i := 0; i+1 < len(data); i += 2 {
+		s += fmt.Sprintf("%02X%02X", data[i+1], data[i])
+	}
+	return s
+}
+
+// bitsField returns values as bit units field of request.
+// binary mode expresses 2 points as 1 byte from upper 4 bits like "1001" (ON, OFF, OFF, ON). odd points are padded with 0.
+// ascii mode expresses 1 point as 1 character like "1001".
+func (c Code) bitsField(values []bool) string {
+	s := ""
+	for _, v := range values {
+		if v {
+			s += "1"
+		} else {
+			s += "0"
+		}
+	}
+	if c == Binary && len(values)%2 == 1 {
+		s += "0"
+	}
+	return s
+}
+
+// byteLen returns number of bytes of request string.
+// binary mode is 1byte=2char, ascii mode is 1byte=1char.
+func (c Code) byteLen(requestStr string) int64 {
+	if c == Ascii {
+		return int64(len(requestStr))
+	}
+	return int64(len(requestStr) / 2)
+}
+
+// frameBytes converts request string to bytes to be sent.
+func (c Code) frameBytes(requestStr string) ([]byte, error) {
+	if c == Ascii {
+		return []byte(requestStr), nil
+	}
+	return hex.DecodeString(requestStr)
+}
+
+// fieldValue returns value of response header field like Response.DataLen.
+// binary mode field is hex stored from lower byte to upper byte like "0C00", ascii mode field is like "000C".
+func (c Code) fieldValue(field string) (uint64, error) {
+	if c == Binary {
+		field = Ascii.layout(field) // reverse byte order
+	}
+	return strconv.ParseUint(field, 16, 64)
+}
+
+// decodeUints decodes n values of size byte from response data.
+// binary mode is little endian, ascii mode is hex characters stored from upper byte to lower byte.
+func (c Code) decodeUints(data []byte, size, n int) ([]uint64, error) {
+	width := size
+	if c == Ascii {
+		width = 2 * size // 1byte=2char
+	}
+	if len(data) < width*n {
+		return nil, fmt.Errorf("response data must be larger than %v byte but actual is %v byte", width*n, len(data))
+	}
+
+	values := make([]uint64, n)
+	for i := range values {
+		b := data[i*width : (i+1)*width]
+		if c == Ascii {
+			v, err := strconv.ParseUint(string(b), 16, 64)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+			continue
+		}
+		for j := size - 1; j >= 0; j-- {
+			values[i] = values[i]<<8 | uint64(b[j])
+		}
+	}
+	return values, nil
+}
+
+// decodeBits decodes n points of bit units response data.
+// binary mode is 2 points per 1 byte from upper 4 bits, and the last lower 4 bits of odd points is padding.
+// ascii mode is 1 point per 1 character "0" or "1".
+func (c Code) decodeBits(data []byte, n int) ([]bool, error) {
+	size := n
+	if c == Binary {
+		size = (n + 1) / 2
+	}
+	if len(data) < size {
+		return nil, fmt.Errorf("response data must be larger than %v byte but actual is %v byte", size, len(data))
+	}
+
+	values := make([]bool, n)
+	for i := range values {
+		var b byte
+		if c == Binary {
+			b = data[i/2] >> (4 * (1 - i%2)) & 0x0F
+		} else {
+			b = data[i] - '0'
+		}
+		if b > 1 {
+			return nil, fmt.Errorf("invalid bit value of point %v", i)
+		}
+		values[i] = b == 1
+	}
+	return values, nil
+}
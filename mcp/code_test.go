@@ -3,6 +3,8 @@ package mcp
 import (
 	"encoding/hex"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestCode_EncodeHex(t *testing.T) {
@@ -25,3 +27,62 @@ func TestCode_EncodeHex(t *testing.T) {
 		}
 	}
 }
+
+func TestCode_uintField(t *testing.T) {
+	cases := []struct {
+		code     Code
+		v        int64
+		size     int
+		expected string
+	}{
+		{code: Binary, v: 300, size: 3, expected: "2C0100"},
+		{code: Ascii, v: 300, size: 3, expected: "00012C"},
+		{code: Binary, v: 5, size: 2, expected: "0500"},
+		{code: Ascii, v: 5, size: 2, expected: "0005"},
+	}
+
+	for _, v := range cases {
+		if actual := v.code.uintField(v.v, v.size); actual != v.expected {
+			t.Errorf("wrong result: expected is %v but actual is %v", v.expected, actual)
+		}
+	}
+}
+
+func TestCode_wordsField(t *testing.T) {
+	data := []byte{0x34, 0x12, 0x02, 0x00}
+
+	if actual := Binary.wordsField(data); actual != "34120200" {
+		t.Errorf("wrong result: expected is %v but actual is %v", "34120200", actual)
+	}
+	if actual := Ascii.wordsField(data); actual != "12340002" {
+		t.Errorf("wrong result: expected is %v but actual is %v", "12340002", actual)
+	}
+}
+
+func TestCode_decodeBits(t *testing.T) {
+	expected := []bool{true, false, false, true, true}
+
+	// 5 and 6 points are the same length in binary mode. the last lower 4 bits is padding
+	binary, err := Binary.decodeBits([]byte{0x10, 0x01, 0x10}, 5)
+	if err != nil {
+		t.Fatalf("unexpected decode err: %v", err)
+	}
+	if diff := cmp.Diff(expected, binary); diff != "" {
+		t.Errorf("(-expected +actual)\n%s", diff)
+	}
+
+	ascii, err := Ascii.decodeBits([]byte("10011"), 5)
+	if err != nil {
+		t.Fatalf("unexpected decode err: %v", err)
+	}
+	if diff := cmp.Diff(expected, ascii); diff != "" {
+		t.Errorf("(-expected +actual)\n%s", diff)
+	}
+
+	if _, err := Binary.decodeBits([]byte{0x10, 0x01}, 5); err == nil {
+		t.Errorf("expected error of short data but actual is nil")
+	}
+	if _, err := Ascii.decodeBits([]byte("10021"), 5); err == nil {
+		t.Errorf("expected error of invalid bit value but actual is nil")
+	}
+}
@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Device is device address like D100.
+type Device struct {
+	// Name is device code name like 'D' register, or extended device specification like J1\W.
+	Name string
+	// Offset is device offset addr.
+	Offset int64
+	// Bit is bit number of word device like F of D100.F. It is used only if HasBit is true.
+	Bit int
+	// HasBit is true if device is bit of word device like D100.F.
+	HasBit bool
+	// Series is series of PLC CPU that decides numbering of device number in String like octal X of MELSEC iQ-F.
+	// zero value is SeriesQ.
+	Series Series
+}
+
+// Block is contiguous device range of multiple block batch read and write like D100-D120.
+type Block struct {
+	// Name is device code name like 'D' register.
+	Name string
+	// Offset is head device offset addr.
+	Offset int64
+	// NumPoints is number of device points. bit device block is word units, so 1 point is 16 bits.
+	NumPoints int64
+}
+
+// deviceNumberPattern is device number part of device address like 100, 1F, 100Z1 or 100.F.
+var deviceNumberPattern = regexp.MustCompile(`^([0-9A-F]+)(?:Z([0-9]+))?(?:\.([0-9A-F]))?$`)
+
+// extensionPrefixPattern is prefix of link direct device and module access device like J1\ or U3\.
+var extensionPrefixPattern = regexp.MustCompile(`^(?:J[0-9]+|U[0-9A-F]+)\\`)
+
+// ParseDevice parses device address of GX Works notation like D100, X1F, ZR20000, D100.F, J1\W100, U3\G100 or D100Z1.
+// device number of X, Y, B, W, SB, SW, DX and DY is hex, and others are decimal.
+// Use Profile.ParseDevice for MELSEC iQ-F and MELSEC-F that X and Y are octal.
+func ParseDevice(address string) (Device, error) {
+	return ProfileOf(SeriesQ).ParseDevice(address)
+}
+
+// ParseDevice parses device address by numbering of series of this profile.
+func (p Profile) ParseDevice(address string) (Device, error) {
+	s := strings.ToUpper(strings.TrimSpace(address))
+
+	prefix := extensionPrefixPattern.FindString(s)
+	s = s[len(prefix):]
+
+	// device code name is longest match like SM of SM400 and ZR of ZR100
+	var device DeviceInfo
+	found := false
+	for n := 4; n > 0 && !found; n-- {
+		if len(s) > n {
+			device, found = deviceCatalogue[s[:n]]
+		}
+	}
+	if !found {
+		return Device{}, fmt.Errorf("device of address %v is unknown", address)
+	}
+
+	m := deviceNumberPattern.FindStringSubmatch(s[len(device.Name):])
+	if m == nil {
+		return Device{}, fmt.Errorf("device number of address %v is invalid", address)
+	}
+
+	base := 10
+	switch device.numbering(p.Series) {
+	case Hexadecimal:
+		base = 16
+	case Octal:
+		base = 8
+	}
+	offset, err := strconv.ParseInt(m[1], base, 64)
+	if err != nil {
+		return Device{}, fmt.Errorf("device number of address %v is invalid: %v", address, err)
+	}
+
+	d := Device{Name: prefix + device.Name, Offset: offset, Series: p.Series}
+	if m[2] != "" {
+		d.Name += "Z" + m[2]
+	}
+	_, ext, err := parseExtendedName(d.Name)
+	if err != nil {
+		return Device{}, err
+	}
+	if ext == nil && extensionOnlyDevices[device.Name] {
+		return Device{}, fmt.Errorf("device of address %v must be specified with module like U3\\%v", address, s)
+	}
+
+	if m[3] != "" {
+		if device.Type != WordDevice {
+			return Device{}, fmt.Errorf("bit number of address %v can be specified only for word device", address)
+		}
+		bit, _ := strconv.ParseInt(m[3], 16, 64)
+		d.Bit, d.HasBit = int(bit), true
+	}
+	return d, nil
+}
+
+// MustParseDevice is like ParseDevice but panics if address can not be parsed.
+func MustParseDevice(address string) Device {
+	d, err := ParseDevice(address)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// String returns device address of GX Works notation like D100, X1F or D100.F.
+// device number is expressed by numbering of Series, so that ParseDevice of the same series returns the same device.
+func (d Device) String() string {
+	name, ext, err := parseExtendedName(d.Name)
+	if err != nil {
+		return fmt.Sprintf("%v%v", d.Name, d.Offset)
+	}
+
+	prefix, index := "", ""
+	if ext != nil {
+		switch ext.directMemory {
+		case DIRECT_MEMORY_LINK:
+			prefix = fmt.Sprintf("J%d\\", ext.number)
+		case DIRECT_MEMORY_MODULE:
+			prefix = fmt.Sprintf("U%X\\", ext.number)
+		}
+		if ext.indexReg >= 0 {
+			index = fmt.Sprintf("Z%d", ext.indexReg)
+		}
+	}
+
+	number := fmt.Sprintf("%d", d.Offset)
+	switch deviceCatalogue[name].numbering(d.Series) {
+	case Hexadecimal:
+		number = fmt.Sprintf("%X", d.Offset)
+	case Octal:
+		number = fmt.Sprintf("%o", d.Offset)
+	}
+
+	s := prefix + name + number + index
+	if d.HasBit {
+		s += fmt.Sprintf(".%X", d.Bit)
+	}
+	return s
+}
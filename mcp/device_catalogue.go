@@ -0,0 +1,123 @@
+package mcp
+
+// DeviceType is data type of device.
+type DeviceType int
+
+const (
+	// BitDevice is 1 bit device like X, M and contact of timer.
+	BitDevice DeviceType = iota
+	// WordDevice is 16 bit device like D, W and current value of timer.
+	WordDevice
+	// DoubleWordDevice is 32 bit device like LTN, LCN and LZ of MELSEC iQ-R.
+	DoubleWordDevice
+)
+
+// Numbering is notation of device number.
+type Numbering int
+
+const (
+	// Decimal device number like D100.
+	Decimal Numbering = iota
+	// Hexadecimal device number like X1F.
+	Hexadecimal
+	// Octal device number like X17 of MELSEC iQ-F.
+	Octal
+)
+
+// DeviceInfo is device code and attributes of device.
+type DeviceInfo struct {
+	// Name is device code name like D.
+	Name string
+	// Code is binary mode device code.
+	Code byte
+	// ASCIICode is ascii mode device code of MELSEC-Q/L like "D*". empty if device is only MELSEC iQ-R.
+	ASCIICode string
+	// IQRASCIICode is ascii mode device code of MELSEC iQ-R like "D***".
+	IQRASCIICode string
+	// Type is data type of device.
+	Type DeviceType
+	// Numbering is notation of device number.
+	Numbering Numbering
+	// IQROnly is true if device can be accessed only by IQRAddressing.
+	IQROnly bool
+}
+
+// deviceCatalogue is all devices that can be accessed by mc protocol.
+// MELSECコミュニケーションプロトコル リファレンス デバイスコード一覧
+var deviceCatalogue = map[string]DeviceInfo{}
+
+func init() {
+	for _, d := range []DeviceInfo{
+		{"SM", 0x91, "SM", "SM**", BitDevice, Decimal, false},     // 特殊リレー
+		{"SD", 0xA9, "SD", "SD**", WordDevice, Decimal, false},    // 特殊レジスタ
+		{"X", 0x9C, "X*", "X***", BitDevice, Hexadecimal, false},  // 入力
+		{"Y", 0x9D, "Y*", "Y***", BitDevice, Hexadecimal, false},  // 出力
+		{"M", 0x90, "M*", "M***", BitDevice, Decimal, false},      // 内部リレー
+		{"L", 0x92, "L*", "L***", BitDevice, Decimal, false},      // ラッチリレー
+		{"F", 0x93, "F*", "F***", BitDevice, Decimal, false},      // アナンシェータ
+		{"V", 0x94, "V*", "V***", BitDevice, Decimal, false},      // エッジリレー
+		{"B", 0xA0, "B*", "B***", BitDevice, Hexadecimal, false},  // リンクリレー
+		{"D", 0xA8, "D*", "D***", WordDevice, Decimal, false},     // データレジスタ
+		{"W", 0xB4, "W*", "W***", WordDevice, Hexadecimal, false}, // リンクレジスタ
+
+		// タイマ
+		{"TS", 0xC1, "TS", "TS**", BitDevice, Decimal, false},
+		{"TC", 0xC0, "TC", "TC**", BitDevice, Decimal, false},
+		{"TN", 0xC2, "TN", "TN**", WordDevice, Decimal, false},
+		{"LTS", 0x51, "", "LTS*", BitDevice, Decimal, true},
+		{"LTC", 0x50, "", "LTC*", BitDevice, Decimal, true},
+		{"LTN", 0x52, "", "LTN*", DoubleWordDevice, Decimal, true},
+
+		// 積算タイマ
+		{"STS", 0xC7, "SS", "STS*", BitDevice, Decimal, false},
+		{"STC", 0xC6, "SC", "STC*", BitDevice, Decimal, false},
+		{"STN", 0xC8, "SN", "STN*", WordDevice, Decimal, false},
+		{"LSTS", 0x59, "", "LSTS", BitDevice, Decimal, true},
+		{"LSTC", 0x58, "", "LSTC", BitDevice, Decimal, true},
+		{"LSTN", 0x5A, "", "LSTN", DoubleWordDevice, Decimal, true},
+
+		// カウンタ
+		{"CS", 0xC4, "CS", "CS**", BitDevice, Decimal, false},
+		{"CC", 0xC3, "CC", "CC**", BitDevice, Decimal, false},
+		{"CN", 0xC5, "CN", "CN**", WordDevice, Decimal, false},
+		{"LCS", 0x55, "", "LCS*", BitDevice, Decimal, true},
+		{"LCC", 0x54, "", "LCC*", BitDevice, Decimal, true},
+		{"LCN", 0x56, "", "LCN*", DoubleWordDevice, Decimal, true},
+
+		{"SB", 0xA1, "SB", "SB**", BitDevice, Hexadecimal, false},  // リンク特殊リレー
+		{"SW", 0xB5, "SW", "SW**", WordDevice, Hexadecimal, false}, // リンク特殊レジスタ
+		{"S", 0x98, "S*", "S***", BitDevice, Decimal, false},       // ステップリレー
+		{"DX", 0xA2, "DX", "DX**", BitDevice, Hexadecimal, false},  // ダイレクトアクセス入力
+		{"DY", 0xA3, "DY", "DY**", BitDevice, Hexadecimal, false},  // ダイレクトアクセス出力
+
+		// インデックスレジスタ
+		{"Z", 0xCC, "Z*", "Z***", WordDevice, Decimal, false},
+		{"LZ", 0x62, "", "LZ**", DoubleWordDevice, Decimal, true},
+
+		// ファイルレジスタ
+		{"R", 0xAF, "R*", "R***", WordDevice, Decimal, false},
+		{"ZR", 0xB0, "ZR", "ZR**", WordDevice, Decimal, false},
+
+		{"RD", 0x2C, "", "RD**", WordDevice, Decimal, true}, // リフレッシュデータレジスタ
+
+		// module access device U□\G. extended device specification is required.
+		{"G", 0xAB, "G*", "G***", WordDevice, Decimal, false},
+	} {
+		deviceCatalogue[d.Name] = d
+	}
+}
+
+// LookupDevice returns device code and attributes of device code name like D.
+func LookupDevice(name string) (DeviceInfo, bool) {
+	d, ok := deviceCatalogue[name]
+	return d, ok
+}
+
+// numbering returns notation of device number of series.
+// X and Y of MELSEC iQ-F and MELSEC-F are octal.
+func (d DeviceInfo) numbering(series Series) Numbering {
+	if (series == SeriesIQF || series == SeriesFX) && (d.Name == "X" || d.Name == "Y") {
+		return Octal
+	}
+	return d.Numbering
+}
@@ -0,0 +1,62 @@
+package mcp
+
+import "testing"
+
+func TestLookupDevice(t *testing.T) {
+	d, ok := LookupDevice("SW")
+	if !ok {
+		t.Fatalf("expected device SW but actual is not found")
+	}
+	if d.Code != 0xB5 || d.Type != WordDevice || d.Numbering != Hexadecimal {
+		t.Fatalf("expected %v but actual is %v", DeviceInfo{"SW", 0xB5, "SW", "SW**", WordDevice, Hexadecimal, false}, d)
+	}
+
+	if _, ok := LookupDevice("QQ"); ok {
+		t.Fatalf("expected unknown device QQ but actual is found")
+	}
+}
+
+func TestStation_DeviceField(t *testing.T) {
+	station := NewLocalStation()
+	asciiStation := NewLocalStation()
+	asciiStation.code = Ascii
+	iqrStation := NewLocalStation()
+	iqrStation.code = Ascii
+	iqrStation.addressing = IQRAddressing
+	iqfStation := NewLocalStation()
+	iqfStation.code = Ascii
+	iqfStation.series = SeriesIQF
+
+	cases := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{"special register", station.deviceField("SD", 100), "640000A9"},
+		{"timer current value", station.deviceField("TN", 10), "0A0000C2"},
+		{"link special relay", station.deviceField("SB", 0x1F), "1F0000A1"},
+		{"ascii retentive timer", asciiStation.deviceField("STN", 10), "SN000010"},
+		{"ascii direct input", asciiStation.deviceField("DX", 0x1F), "DX00001F"},
+		{"iQ-R ascii retentive timer", iqrStation.deviceField("STN", 10), "STN*0000000010"},
+		{"iQ-F ascii octal input", iqfStation.deviceField("X", 15), "X*000017"},
+	}
+	for _, v := range cases {
+		if v.actual != v.expected {
+			t.Errorf("%v: expected %v but actual is %v", v.name, v.expected, v.actual)
+		}
+	}
+}
+
+func TestStation_BuildRequestUnknownDevice(t *testing.T) {
+	station := NewLocalStation()
+
+	if _, err := station.BuildReadRequest("QQ", 0, 1); err == nil {
+		t.Fatalf("expected error of unknown device but actual is nil")
+	}
+	if _, err := station.BuildRandomReadRequest([]Device{{Name: "D", Offset: 0}, {Name: "QQ", Offset: 0}}, nil); err == nil {
+		t.Fatalf("expected error of unknown device but actual is nil")
+	}
+	if _, err := station.BuildBlockReadRequest([]Block{{Name: "RD", Offset: 0, NumPoints: 1}}, nil); err == nil {
+		t.Fatalf("expected error of iQ-R only device but actual is nil")
+	}
+}
@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseDevice(t *testing.T) {
+	cases := []struct {
+		address  string
+		expected Device
+	}{
+		{"D100", Device{Name: "D", Offset: 100}},
+		{"x1f", Device{Name: "X", Offset: 0x1F}},
+		{"ZR20000", Device{Name: "ZR", Offset: 20000}},
+		{"SM400", Device{Name: "SM", Offset: 400}},
+		{"SW1A", Device{Name: "SW", Offset: 0x1A}},
+		{"LSTN10", Device{Name: "LSTN", Offset: 10}},
+		{"D100.F", Device{Name: "D", Offset: 100, Bit: 15, HasBit: true}},
+		{`J1\W100`, Device{Name: `J1\W`, Offset: 0x100}},
+		{`U3\G100`, Device{Name: `U3\G`, Offset: 100}},
+		{"D100Z1", Device{Name: "DZ1", Offset: 100}},
+	}
+	for _, c := range cases {
+		d, err := ParseDevice(c.address)
+		if err != nil {
+			t.Fatalf("unexpected parse err of %v: %v", c.address, err)
+		}
+		if diff := cmp.Diff(c.expected, d); diff != "" {
+			t.Errorf("%v: (-expected +actual)\n%s", c.address, diff)
+		}
+	}
+
+	for _, address := range []string{"QQ100", "D", "D1G", "M10.1", "G100", `J1\G100`} {
+		if _, err := ParseDevice(address); err == nil {
+			t.Errorf("expected error of %v but actual is nil", address)
+		}
+	}
+}
+
+func TestProfile_ParseDevice(t *testing.T) {
+	d, err := ProfileOf(SeriesIQF).ParseDevice("X17")
+	if err != nil {
+		t.Fatalf("unexpected parse err: %v", err)
+	}
+	if d.Offset != 15 {
+		t.Fatalf("expected %v but actual is %v", 15, d.Offset)
+	}
+	if s := d.String(); s != "X17" {
+		t.Fatalf("expected %v but actual is %v", "X17", s)
+	}
+
+	if _, err := ProfileOf(SeriesIQF).ParseDevice("X18"); err == nil {
+		t.Fatalf("expected error of octal device number but actual is nil")
+	}
+}
+
+func TestDevice_String(t *testing.T) {
+	for _, address := range []string{"D100", "X1F", "D100.F", `J1\W100`, `U3\G100`, "D100Z1"} {
+		if s := MustParseDevice(address).String(); s != address {
+			t.Errorf("expected %v but actual is %v", address, s)
+		}
+	}
+}
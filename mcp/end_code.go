@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"fmt"
+)
+
+// ERR_INFO_SIZE is size of error information that follows error end code of 3E and 4E frame response.
+// [network num + pc num + request unit i/o num + request unit station num + command + sub command]
+const ERR_INFO_SIZE = 9
+
+// endCodeDescriptions is end code and description map of 3E and 4E frame.
+// 4xxx is detected by plc cpu, and Cxxx is detected by ethernet module.
+var endCodeDescriptions = map[uint16]string{
+	0x4000: "serial communication checksum error is detected by plc cpu",
+	0x4001: "request that is not supported by plc cpu is executed",
+	0x4002: "request that is not supported by plc cpu is executed",
+	0x4010: "request can not be executed because plc cpu is running",
+	0x4013: "request can not be executed because plc cpu is running",
+	0x4030: "specified device is not supported by plc cpu",
+	0x4031: "specified device number is out of range",
+	0x4041: "specified range exceeds the range of buffer memory of intelligent function module",
+	0x4043: "specified intelligent function module does not exist",
+	0x4080: "request data is wrong",
+	0x408B: "remote request can not be executed in current state of plc cpu",
+	0xC050: "ascii code data that can not be converted to binary is received",
+	0xC051: "number of bit points is out of range",
+	0xC052: "number of word points is out of range",
+	0xC053: "number of bit points of random access is out of range",
+	0xC054: "number of word points of random access is out of range",
+	0xC056: "read or write range exceeds the maximum address",
+	0xC058: "request data length does not match number of characters of data",
+	0xC059: "command or sub command is wrong, or it is not supported by plc cpu",
+	0xC05B: "plc cpu can not read or write the specified device",
+	0xC05C: "request content is wrong, like bit units access to word device",
+	0xC05D: "monitor registration is not executed",
+	0xC05F: "request can not be executed to target plc cpu",
+	0xC060: "request content is wrong, like incorrect data of bit device",
+	0xC061: "request data length does not match number of data",
+	0xC06F: "communication data code of request is different from setting of ethernet module",
+	0xC070: "device memory extension specification can not be used for target station",
+	0xC0B5: "data that can not be handled by plc cpu is specified",
+	0xC200: "remote password is mismatched",
+	0xC201: "port is locked by remote password",
+	0xC204: "remote password unlock is requested by other device",
+}
+
+// ErrInfo is error information of error end code response. It identifies the failed request.
+type ErrInfo struct {
+	// network number of station that returns error
+	NetworkNum uint8
+	// PC number of station that returns error
+	PCNum uint8
+	// request destination unit I/O number
+	UnitIONum uint16
+	// request destination unit station number
+	UnitStationNum uint8
+	// command of the failed request like 0x0401
+	Command uint16
+	// sub command of the failed request like 0x0000
+	SubCommand uint16
+}
+
+// EndCodeError is returned when plc returns error end code. Use errors.As to get it.
+type EndCodeError struct {
+	// EndCode is end code of response like 0xC059.
+	EndCode uint16
+	// ErrInfo is error information of response. It is nil if response has no error information.
+	ErrInfo *ErrInfo
+}
+
+// Description returns description of end code. It is empty if end code is not in the catalogue.
+func (e *EndCodeError) Description() string {
+	return endCodeDescriptions[e.EndCode]
+}
+
+func (e *EndCodeError) Error() string {
+	msg := fmt.Sprintf("plc returns end code %04X", e.EndCode)
+	if desc := e.Description(); desc != "" {
+		msg += ": " + desc
+	}
+	if e.ErrInfo != nil {
+		msg += fmt.Sprintf(" (command %04X, sub command %04X, network %v, pc %02X)",
+			e.ErrInfo.Command, e.ErrInfo.SubCommand, e.ErrInfo.NetworkNum, e.ErrInfo.PCNum)
+	}
+	return msg
+}
+
+// endCodeError returns EndCodeError if end code of response is not 0000, or nil if response is normal end.
+func endCodeError(response *Response, code Code) error {
+	endCode, err := code.fieldValue(response.EndCode)
+	if err != nil {
+		return fmt.Errorf("invalid end code %v: %v", response.EndCode, err)
+	}
+	if endCode == 0 {
+		return nil
+	}
+
+	e := &EndCodeError{EndCode: uint16(endCode)}
+	if info, err := parseErrInfo(response.ErrInfo, code); err == nil {
+		e.ErrInfo = info
+	}
+	return e
+}
+
+// parseErrInfo parses error information of response.
+// binary mode fields are stored from lower byte to upper byte, and ascii mode fields are hex characters from upper byte.
+func parseErrInfo(data []byte, code Code) (*ErrInfo, error) {
+	// 1byte=2char in ascii code
+	width := 1
+	if code == Ascii {
+		width = 2
+	}
+	if len(data) < ERR_INFO_SIZE*width {
+		return nil, fmt.Errorf("error information must be larger than %v byte but actual is %v byte", ERR_INFO_SIZE*width, len(data))
+	}
+
+	// field returns value of next size byte field
+	pos := 0
+	field := func(size int) (uint64, error) {
+		b := data[pos : pos+size*width]
+		pos += size * width
+		if code == Ascii {
+			return code.fieldValue(string(b))
+		}
+		return code.fieldValue(fmt.Sprintf("%X", b))
+	}
+
+	values := make([]uint64, 0, 6)
+	for _, size := range []int{1, 1, 2, 1, 2, 2} {
+		v, err := field(size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid error information: %v", err)
+		}
+		values = append(values, v)
+	}
+	return &ErrInfo{
+		NetworkNum:     uint8(values[0]),
+		PCNum:          uint8(values[1]),
+		UnitIONum:      uint16(values[2]),
+		UnitStationNum: uint8(values[3]),
+		Command:        uint16(values[4]),
+		SubCommand:     uint16(values[5]),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"encoding/hex"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEndCodeError(t *testing.T) {
+	expectedInfo := &ErrInfo{NetworkNum: 0, PCNum: 0xFF, UnitIONum: 0x03FF, UnitStationNum: 0, Command: 0x0401, SubCommand: 0x0000}
+
+	// binary: end code C059 and error information
+	binaryResp, _ := hex.DecodeString("d00000ffff03000b0059c000ffff030001040000")
+	response, err := NewParser().Do(binaryResp)
+	if err != nil {
+		t.Fatalf("unexpected parse err: %v", err)
+	}
+	if err := checkDataLen(response, Binary); err != nil {
+		t.Fatalf("unexpected data length err: %v", err)
+	}
+	var binaryErr *EndCodeError
+	if !errors.As(endCodeError(response, Binary), &binaryErr) {
+		t.Fatalf("expected EndCodeError but actual is %v", endCodeError(response, Binary))
+	}
+	if diff := cmp.Diff(&EndCodeError{EndCode: 0xC059, ErrInfo: expectedInfo}, binaryErr); diff != "" {
+		t.Errorf("(-expected +actual)\n%s", diff)
+	}
+	expectedMsg := "plc returns end code C059: command or sub command is wrong, or it is not supported by plc cpu (command 0401, sub command 0000, network 0, pc FF)"
+	if binaryErr.Error() != expectedMsg {
+		t.Errorf("expected %v but actual is %v", expectedMsg, binaryErr.Error())
+	}
+
+	// ascii: same response
+	asciiResp := []byte("D00000FF03FF00" + "0016" + "C059" + "00FF03FF00" + "0401" + "0000")
+	response, err = NewParser().Do(asciiResp)
+	if err != nil {
+		t.Fatalf("unexpected parse err: %v", err)
+	}
+	var asciiErr *EndCodeError
+	if !errors.As(endCodeError(response, Ascii), &asciiErr) {
+		t.Fatalf("expected EndCodeError but actual is %v", endCodeError(response, Ascii))
+	}
+	if diff := cmp.Diff(&EndCodeError{EndCode: 0xC059, ErrInfo: expectedInfo}, asciiErr); diff != "" {
+		t.Errorf("(-expected +actual)\n%s", diff)
+	}
+
+	// unknown end code without error information
+	unknown := &EndCodeError{EndCode: 0xCFFF}
+	if unknown.Error() != "plc returns end code CFFF" {
+		t.Errorf("expected %v but actual is %v", "plc returns end code CFFF", unknown.Error())
+	}
+
+	// normal end
+	normal, _ := NewParser().Do([]byte("D00000FF03FF00" + "0004" + "0000"))
+	if err := endCodeError(normal, Ascii); err != nil {
+		t.Errorf("unexpected end code err: %v", err)
+	}
+}
+
+func TestClient3E_ReadEndCodeError(t *testing.T) {
+	// end code C056 and error information
+	errResp, _ := hex.DecodeString("d00000ffff03000b0056c000ffff030001040000")
+	plc := startTCPPLC(t, func(req []byte) []byte {
+		return errResp
+	})
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	resp, err := client.Read("D", 65535, 10)
+	var endCodeErr *EndCodeError
+	if !errors.As(err, &endCodeErr) || endCodeErr.EndCode != 0xC056 {
+		t.Fatalf("expected end code %X but actual is %v", 0xC056, err)
+	}
+	if endCodeErr.ErrInfo == nil || endCodeErr.ErrInfo.Command != 0x0401 {
+		t.Fatalf("expected command %X but actual is %v", 0x0401, endCodeErr.ErrInfo)
+	}
+	// error response is returned as it is
+	if hex.EncodeToString(resp) != hex.EncodeToString(errResp) {
+		t.Fatalf("expected %X but actual is %X", errResp, resp)
+	}
+
+	if _, err := client.ReadWords("D", 65535, 10); !errors.As(err, &endCodeErr) {
+		t.Fatalf("expected EndCodeError but actual is %v", err)
+	}
+	if _, err := client.BitWrite("M", 0, []bool{true}); !errors.As(err, &endCodeErr) {
+		t.Fatalf("expected EndCodeError but actual is %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Extended device specification accesses link direct device like J1\W100, module access device like U3\G100
+// and index modified device like D100Z1 by batch read and write command.
+// MELSECコミュニケーションプロトコル リファレンス 拡張指定
+const (
+	DIRECT_MEMORY_LINK   = 0xF9 // link direct device J□\
+	DIRECT_MEMORY_MODULE = 0xF8 // module access device U□\
+
+	INDEX_MODIFICATION_Z = 0x40 // index modification by Z
+)
+
+// extendedSubCommands is sub command of extended device specification for each sub command. binary mode expression.
+var extendedSubCommands = map[Addressing]map[string]string{
+	QAddressing: {
+		"0000": "8000", // word units
+		"0100": "8100", // bit units
+	},
+	IQRAddressing: {
+		"0000": "8200", // word units
+		"0100": "8300", // bit units
+	},
+}
+
+// extensionOnlyDevices is devices that can be accessed only by extended device specification.
+var extensionOnlyDevices = map[string]bool{
+	"G": true, // U□\G
+}
+
+// extendedNamePattern is deviceName with extended specification like J1\W, U3\G or DZ1.
+// device code name is shortest match, so that ZR and LZ are not index modification.
+var extendedNamePattern = regexp.MustCompile(`^(?:(J)([0-9]+)\\|(U)([0-9A-Fa-f]+)\\)?([A-Z]+?)(?:Z([0-9]+))?$`)
+
+// extension is extended device specification of device.
+type extension struct {
+	// direct memory specification. 0 if device is not link direct device nor module access device.
+	directMemory int64
+	// network number of link direct device, or upper 3 digits of start I/O number of module access device.
+	number int64
+	// index register number. -1 if device is not index modified.
+	indexReg int64
+}
+
+// parseExtendedName splits deviceName into device code name and extended specification.
+// extension is nil if deviceName is plain device code name like D.
+func parseExtendedName(deviceName string) (string, *extension, error) {
+	if _, ok := deviceCatalogue[deviceName]; ok {
+		return deviceName, nil, nil
+	}
+
+	m := extendedNamePattern.FindStringSubmatch(deviceName)
+	if m == nil {
+		return "", nil, fmt.Errorf("device %v is not supported", deviceName)
+	}
+
+	ext := &extension{indexReg: -1}
+	switch {
+	case m[1] == "J":
+		n, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid network number of device %v: %v", deviceName, err)
+		}
+		ext.directMemory, ext.number = DIRECT_MEMORY_LINK, n
+	case m[3] == "U":
+		n, err := strconv.ParseInt(m[4], 16, 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid start I/O number of device %v: %v", deviceName, err)
+		}
+		ext.directMemory, ext.number = DIRECT_MEMORY_MODULE, n
+	}
+	if m[6] != "" {
+		z, err := strconv.ParseInt(m[6], 10, 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid index register of device %v: %v", deviceName, err)
+		}
+		ext.indexReg = z
+	}
+
+	name := m[5]
+	if _, ok := deviceCatalogue[name]; !ok {
+		return "", nil, fmt.Errorf("device %v is not supported", deviceName)
+	}
+	if extensionOnlyDevices[name] && ext.directMemory != DIRECT_MEMORY_MODULE {
+		return "", nil, fmt.Errorf("device %v must be specified with module like U3\\%v", deviceName, name)
+	}
+	if ext.directMemory == DIRECT_MEMORY_MODULE && !extensionOnlyDevices[name] {
+		return "", nil, fmt.Errorf("device %v is not module access device", deviceName)
+	}
+	return name, ext, nil
+}
+
+// deviceSpec returns sub command and device field of batch read and write.
+// If deviceName has extended specification, sub command of extended device specification is used.
+// subCommand is MELSEC-Q/L sub command.
+func (h *station) deviceSpec(subCommand, deviceName string, offset int64) (string, error) {
+	name, ext, err := parseExtendedName(deviceName)
+	if err != nil {
+		return "", err
+	}
+	if ext == nil {
+		return h.code.layout(h.addressing.subCommand(subCommand)) + h.deviceField(deviceName, offset), nil
+	}
+	return h.code.layout(extendedSubCommands[h.addressing][subCommand]) + h.extendedDeviceField(name, offset, ext), nil
+}
+
+// extendedDeviceField returns device field with extended specification.
+// binary mode is extension specification modification[2byte], device modification[2byte], device number and device code,
+// extension specification[2byte] and direct memory specification[1byte].
+func (h *station) extendedDeviceField(deviceName string, offset int64, ext *extension) string {
+	if h.code == Ascii {
+		return h.extendedDeviceFieldASCII(deviceName, offset, ext)
+	}
+
+	// device modification is index register number[1byte] and modification type[1byte]
+	var modification int64
+	if ext.indexReg >= 0 {
+		modification = INDEX_MODIFICATION_Z<<8 | ext.indexReg
+	}
+
+	return h.code.uintField(0, 2) + // extension specification modification is not used
+		h.code.uintField(modification, 2) +
+		h.deviceField(deviceName, offset) +
+		h.code.uintField(ext.number, 2) +
+		h.code.uintField(ext.directMemory, 1)
+}
+
+// extendedDeviceFieldASCII returns device field with extended specification of ascii mode.
+// ascii mode is not character expression of binary mode. It is extension specification[4char],
+// extension specification modification[3char], device code and device number, and device modification[3char].
+// extension specification is J and network number[3char decimal] for link direct device, U and start I/O number[3char hex]
+// for module access device, and 0000 for the other device. direct memory specification is expressed by J and U.
+// device modification is Z and index register number[2char decimal], or 000 if device is not index modified.
+func (h *station) extendedDeviceFieldASCII(deviceName string, offset int64, ext *extension) string {
+	extSpec := "0000"
+	switch ext.directMemory {
+	case DIRECT_MEMORY_LINK:
+		extSpec = fmt.Sprintf("J%03d", ext.number)
+	case DIRECT_MEMORY_MODULE:
+		extSpec = fmt.Sprintf("U%03X", ext.number)
+	}
+
+	modification := "000"
+	if ext.indexReg >= 0 {
+		modification = fmt.Sprintf("Z%02d", ext.indexReg)
+	}
+
+	return extSpec +
+		"000" + // extension specification modification is not used
+		h.deviceField(deviceName, offset) +
+		modification
+}
+
+// validateExtendedDevice checks that device with extended specification can be accessed by this station.
+func (h *station) validateExtendedDevice(deviceName string) error {
+	name, ext, err := parseExtendedName(deviceName)
+	if err != nil {
+		return err
+	}
+	if ext == nil && extensionOnlyDevices[name] {
+		return fmt.Errorf("device %v must be specified with module like U3\\%v", deviceName, name)
+	}
+	if extensionOnlyDevices[name] {
+		return nil
+	}
+	return h.validateDevice(name)
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import "testing"
+
+func TestParseExtendedName(t *testing.T) {
+	cases := []struct {
+		deviceName string
+		name       string
+		expected   *extension
+	}{
+		{"D", "D", nil},
+		{"ZR", "ZR", nil},
+		{`J1\W`, "W", &extension{directMemory: DIRECT_MEMORY_LINK, number: 1, indexReg: -1}},
+		{`U3A\G`, "G", &extension{directMemory: DIRECT_MEMORY_MODULE, number: 0x3A, indexReg: -1}},
+		{"DZ1", "D", &extension{indexReg: 1}},
+		{`J2\WZ10`, "W", &extension{directMemory: DIRECT_MEMORY_LINK, number: 2, indexReg: 10}},
+	}
+	for _, c := range cases {
+		name, ext, err := parseExtendedName(c.deviceName)
+		if err != nil {
+			t.Fatalf("unexpected parse err: %v", err)
+		}
+		if name != c.name {
+			t.Fatalf("expected %v but actual is %v", c.name, name)
+		}
+		if (ext == nil) != (c.expected == nil) || (ext != nil && *ext != *c.expected) {
+			t.Fatalf("expected %v but actual is %v", c.expected, ext)
+		}
+	}
+
+	for _, deviceName := range []string{`J1\G`, `U3\D`, "QQ", `J1\QQ`} {
+		if _, _, err := parseExtendedName(deviceName); err == nil {
+			t.Fatalf("expected error of %v but actual is nil", deviceName)
+		}
+	}
+}
+
+func TestStation_BuildExtendedReadRequest(t *testing.T) {
+	station := NewLocalStation()
+
+	request := mustRequest(t)(station.BuildReadRequest(`J1\W`, 0x100, 2))
+	expected := "500000FFFF0300" + "1300" + "1000" + "0104" + "8000" + "0000" + "0000" + "000100B4" + "0100" + "F9" + "0200"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+
+	request2 := mustRequest(t)(station.BuildReadRequest(`U3\G`, 100, 2))
+	expected2 := "500000FFFF0300" + "1300" + "1000" + "0104" + "8000" + "0000" + "0000" + "640000AB" + "0300" + "F8" + "0200"
+	if request2 != expected2 {
+		t.Fatalf("expected %v but actual is %v", expected2, request2)
+	}
+
+	station.addressing = IQRAddressing
+	request3 := mustRequest(t)(station.BuildBitReadRequest("MZ4", 10, 1))
+	expected3 := "500000FFFF0300" + "1500" + "1000" + "0104" + "8300" + "0000" + "0440" + "0A0000009000" + "0000" + "00" + "0100"
+	if request3 != expected3 {
+		t.Fatalf("expected %v but actual is %v", expected3, request3)
+	}
+}
+
+func TestStation_BuildExtendedAsciiRequest(t *testing.T) {
+	station := NewLocalStation()
+	station.code = Ascii
+
+	cases := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{
+			name:     "link direct device",
+			actual:   mustRequest(t)(station.BuildReadRequest(`J1\W`, 0x100, 2)),
+			expected: "500000FF03FF00" + "0022" + "0010" + "0401" + "0080" + "J001" + "000" + "W*000100" + "000" + "0002",
+		},
+		{
+			name:     "module access device",
+			actual:   mustRequest(t)(station.BuildReadRequest(`U3A\G`, 100, 2)),
+			expected: "500000FF03FF00" + "0022" + "0010" + "0401" + "0080" + "U03A" + "000" + "G*000100" + "000" + "0002",
+		},
+		{
+			name:     "index modified link direct device",
+			actual:   mustRequest(t)(station.BuildWriteRequest(`J2\WZ10`, 0x10, 1, []byte{0x34, 0x12})),
+			expected: "500000FF03FF00" + "0026" + "0010" + "1401" + "0080" + "J002" + "000" + "W*000010" + "Z10" + "0001" + "1234",
+		},
+	}
+	for _, c := range cases {
+		if c.actual != c.expected {
+			t.Errorf("%v: expected %v but actual is %v", c.name, c.expected, c.actual)
+		}
+	}
+
+	station.addressing = IQRAddressing
+	request := mustRequest(t)(station.BuildBitReadRequest("MZ4", 10, 1))
+	expected := "500000FF03FF00" + "0028" + "0010" + "0401" + "0083" + "0000" + "000" + "M***0000000010" + "Z04" + "0001"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+}
+
+func TestStation_DeviceSpecError(t *testing.T) {
+	station := NewLocalStation()
+	if _, err := station.deviceSpec(READ_SUB_COMMAND, `J1\QQ`, 0); err == nil {
+		t.Fatalf("expected error of %v but actual is nil", `J1\QQ`)
+	}
+}
+
+func TestStation_ValidateExtendedDevice(t *testing.T) {
+	station := NewLocalStation()
+	for _, deviceName := range []string{"D", `J1\W`, `U3\G`, "DZ1"} {
+		if err := station.validateExtendedDevice(deviceName); err != nil {
+			t.Errorf("unexpected validate err: %v", err)
+		}
+	}
+	for _, deviceName := range []string{"G", `J1\G`, `J1\LTN`} {
+		if err := station.validateExtendedDevice(deviceName); err == nil {
+			t.Errorf("expected error of %v but actual is nil", deviceName)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrFrameTruncated is returned when connection is closed before complete response frame is received.
+	ErrFrameTruncated = errors.New("mcp: response frame is truncated")
+	// ErrFrameTooLarge is returned when response frame is larger than expected size of the request.
+	ErrFrameTooLarge = errors.New("mcp: response frame is too large")
+	// ErrFrameMismatch is returned when sub header, station or serial number of response frame does not match the request.
+	ErrFrameMismatch = errors.New("mcp: response frame does not match request")
+)
+
+// frameReader reads one response frame from r.
+// maxSize is expected size of response frame. It is upper limit for 3E and 4E frame, and exact size of normal response for 1E frame.
+type frameReader func(r io.Reader, maxSize int64) ([]byte, error)
+
+// frameReader3E returns frameReader of 3E or 4E frame response.
+// It reads header until response data length, and then reads response data length bytes.
+func frameReader3E(code Code, frame4E bool) frameReader {
+	// 1byte=2char in ascii code
+	width := 1
+	if code == Ascii {
+		width = 2
+	}
+
+	// sub header[2byte] + network num[1byte] + pc num[1byte] + unit i/o num[2byte] + unit station num[1byte] + response data length[2byte]
+	headerLen := 9 * width
+	subHeader := RESP_SUB_HEADER
+	if frame4E {
+		headerLen += 4 * width // serial number[2byte] + fixed value[2byte]
+		subHeader = RESP_SUB_HEADER_4E
+	}
+
+	return func(r io.Reader, maxSize int64) ([]byte, error) {
+		header := make([]byte, headerLen)
+		if err := readFull(r, header); err != nil {
+			return nil, err
+		}
+
+		actualSubHeader := string(header[0:4])
+		if code == Binary {
+			actualSubHeader = fmt.Sprintf("%X", header[0:2])
+		}
+		if actualSubHeader != subHeader {
+			return nil, fmt.Errorf("%w: sub header is %v but expected is %v", ErrFrameMismatch, actualSubHeader, subHeader)
+		}
+
+		dataLenField := string(header[headerLen-4 : headerLen])
+		if code == Binary {
+			dataLenField = fmt.Sprintf("%X", header[headerLen-2:headerLen])
+		}
+		dataLen, err := code.fieldValue(dataLenField)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response data length %v: %v", dataLenField, err)
+		}
+		if frameLen := int64(headerLen) + int64(dataLen); frameLen > maxSize {
+			return nil, fmt.Errorf("%w: response frame is %v byte but expected is at most %v byte", ErrFrameTooLarge, frameLen, maxSize)
+		}
+
+		frame := make([]byte, headerLen+int(dataLen))
+		copy(frame, header)
+		if err := readFull(r, frame[headerLen:]); err != nil {
+			return nil, err
+		}
+		return frame, nil
+	}
+}
+
+// readFrame1E reads 1E frame response. 1E frame response has no data length,
+// so size is exact size of normal response that is decided by the request.
+// abnormal response is sub header[1byte] and completion code[1byte], and abnormal code[1byte] if completion code is 5B.
+func readFrame1E(r io.Reader, size int64) ([]byte, error) {
+	header := make([]byte, 2)
+	if err := readFull(r, header); err != nil {
+		return nil, err
+	}
+
+	switch fmt.Sprintf("%X", header[1:2]) {
+	case END_CODE_1E_NORMAL:
+		frame := make([]byte, size)
+		copy(frame, header)
+		if err := readFull(r, frame[2:]); err != nil {
+			return nil, err
+		}
+		return frame, nil
+	case END_CODE_1E_ABNORMAL:
+		frame := make([]byte, 3)
+		copy(frame, header)
+		if err := readFull(r, frame[2:]); err != nil {
+			return nil, err
+		}
+		return frame, nil
+	default:
+		return header, nil
+	}
+}
+
+// readFull reads len(b) bytes. If r ends before len(b) bytes, ErrFrameTruncated is returned.
+func readFull(r io.Reader, b []byte) error {
+	n, err := io.ReadFull(r, b)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: %v of %v byte is received", ErrFrameTruncated, n, len(b))
+	}
+	return err
+}
@@ -0,0 +1,161 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// startSegmentPLC starts tcp server that sends segments with interval for each request, and then closes connection if closeAfter is true.
+func startSegmentPLC(t *testing.T, segments [][]byte, closeAfter bool) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen err: %v", err)
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buff := make([]byte, UDP_READ_BUFFER_SIZE)
+				for {
+					if _, err := conn.Read(buff); err != nil {
+						return
+					}
+					for _, segment := range segments {
+						if _, err := conn.Write(segment); err != nil {
+							return
+						}
+						time.Sleep(10 * time.Millisecond)
+					}
+					if closeAfter {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return l
+}
+
+func TestClient3E_ReadSegments(t *testing.T) {
+	// D100-D102 response is received in 3 segments
+	segments := [][]byte{
+		{0xD0, 0x00, 0x00, 0xFF},
+		{0xFF, 0x03, 0x00, 0x08, 0x00, 0x00, 0x00, 0x34},
+		{0x12, 0x02, 0x00, 0x03, 0x00},
+	}
+	plc := startSegmentPLC(t, segments, false)
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	words, err := client.ReadWords("D", 100, 3)
+	if err != nil {
+		t.Fatalf("unexpected read err: %v", err)
+	}
+	if diff := cmp.Diff([]uint16{0x1234, 0x0002, 0x0003}, words); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+}
+
+func TestClient3E_InvalidFrame(t *testing.T) {
+	cases := []struct {
+		name       string
+		resp       string
+		closeAfter bool
+		frame4E    bool
+		expected   error
+	}{
+		{
+			name:       "truncated data",
+			resp:       "d00000ffff030008000000341202",
+			closeAfter: true,
+			expected:   ErrFrameTruncated,
+		},
+		{
+			name:       "truncated header",
+			resp:       "d00000ff",
+			closeAfter: true,
+			expected:   ErrFrameTruncated,
+		},
+		{
+			name:     "too large data length",
+			resp:     "d00000ffff0300ff0f00" + "00",
+			expected: ErrFrameTooLarge,
+		},
+		{
+			name:     "wrong sub header",
+			resp:     "d40000ffff030008000000341202000300",
+			expected: ErrFrameMismatch,
+		},
+		{
+			name:     "wrong station",
+			resp:     "d00001ffff030008000000341202000300",
+			expected: ErrFrameMismatch,
+		},
+		{
+			name:     "wrong serial number",
+			resp:     "d400ffff000000ffff030008000000341202000300",
+			frame4E:  true,
+			expected: ErrFrameMismatch,
+		},
+	}
+
+	for _, v := range cases {
+		resp, _ := hex.DecodeString(v.resp)
+		plc := startSegmentPLC(t, [][]byte{resp}, v.closeAfter)
+
+		port := plc.Addr().(*net.TCPAddr).Port
+		client, err := New3EClient("127.0.0.1", port, NewLocalStation())
+		if v.frame4E {
+			client, err = New4EClient("127.0.0.1", port, NewLocalStation())
+		}
+		if err != nil {
+			t.Fatalf("unexpected client err: %v", err)
+		}
+
+		if _, err := client.Read("D", 100, 3); !errors.Is(err, v.expected) {
+			t.Errorf("%v: expected %v but actual is %v", v.name, v.expected, err)
+		}
+		plc.Close()
+	}
+}
+
+func TestReadFrame1E(t *testing.T) {
+	cases := []struct {
+		name     string
+		resp     []byte
+		size     int64
+		expected []byte
+	}{
+		{name: "normal", resp: []byte{0x81, 0x00, 0x34, 0x12, 0xFF}, size: 4, expected: []byte{0x81, 0x00, 0x34, 0x12}},
+		{name: "abnormal code", resp: []byte{0x81, 0x5B, 0x10, 0xFF}, size: 4, expected: []byte{0x81, 0x5B, 0x10}},
+		{name: "error completion code", resp: []byte{0x81, 0x56, 0xFF}, size: 4, expected: []byte{0x81, 0x56}},
+	}
+	for _, v := range cases {
+		frame, err := readFrame1E(bytes.NewReader(v.resp), v.size)
+		if err != nil {
+			t.Fatalf("%v: unexpected read err: %v", v.name, err)
+		}
+		if diff := cmp.Diff(v.expected, frame); diff != "" {
+			t.Errorf("%v: (-expected +actual)\n%s", v.name, diff)
+		}
+	}
+
+	if _, err := readFrame1E(bytes.NewReader([]byte{0x81, 0x00, 0x34}), 4); !errors.Is(err, ErrFrameTruncated) {
+		t.Errorf("expected %v but actual is %v", ErrFrameTruncated, err)
+	}
+}
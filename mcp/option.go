@@ -0,0 +1,129 @@
+package mcp
+
+import "time"
+
+// Option is optional setting of mcp client.
+type Option func(*options)
+
+type options struct {
+	// PLC data communication code
+	code Code
+	// device specification layout
+	addressing Addressing
+	// communication settings and limits of PLC CPU series
+	profile Profile
+	// detect profile by CPU model name when client is created
+	autoProfile bool
+	// use udp instead of tcp
+	udp bool
+	// time to wait response datagram of udp
+	udpTimeout time.Duration
+	// period of tcp keep-alive probe of persistent connection
+	keepAlive time.Duration
+	// backoff before dial after dial failure
+	minBackoff, maxBackoff time.Duration
+	// handler of persistent connection event
+	onConnEvent func(ConnEvent)
+	// maximum number of in-flight requests
+	maxInFlight int
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		code:        Binary,
+		addressing:  QAddressing,
+		profile:     ProfileOf(SeriesQ),
+		udpTimeout:  UDP_DEFAULT_TIMEOUT,
+		keepAlive:   TCP_KEEP_ALIVE_PERIOD,
+		minBackoff:  RECONNECT_MIN_BACKOFF,
+		maxBackoff:  RECONNECT_MAX_BACKOFF,
+		maxInFlight: DEFAULT_MAX_IN_FLIGHT,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithCode sets data communication code of client. default is Binary.
+// It must be same as communication data code setting of PLC ethernet module.
+func WithCode(code Code) Option {
+	return func(o *options) {
+		o.code = code
+	}
+}
+
+// WithAddressing sets device specification layout of client. default is QAddressing.
+// Use IQRAddressing to access large ZR/R ranges and long devices (LTN, LCN, LZ etc.) of MELSEC iQ-R.
+func WithAddressing(addressing Addressing) Option {
+	return func(o *options) {
+		o.addressing = addressing
+	}
+}
+
+// WithUDP makes client communicate with PLC by udp. each request is sent as one datagram.
+// timeout is time to wait response datagram. If timeout is 0, UDP_DEFAULT_TIMEOUT is used.
+func WithUDP(timeout time.Duration) Option {
+	return func(o *options) {
+		o.udp = true
+		if timeout > 0 {
+			o.udpTimeout = timeout
+		}
+	}
+}
+
+// WithProfile sets communication settings and limits of PLC CPU series. default is ProfileOf(SeriesQ).
+// addressing of client is also set to profile.Addressing.
+func WithProfile(profile Profile) Option {
+	return func(o *options) {
+		o.profile = profile
+		o.addressing = profile.Addressing
+	}
+}
+
+// WithAutoProfile makes client read CPU model name when client is created, and use the profile of its series.
+// Detected profile takes precedence over WithProfile and WithAddressing.
+func WithAutoProfile() Option {
+	return func(o *options) {
+		o.autoProfile = true
+	}
+}
+
+// WithKeepAlive sets period of tcp keep-alive probe of persistent connection. default is TCP_KEEP_ALIVE_PERIOD.
+// Negative period disables keep-alive.
+func WithKeepAlive(period time.Duration) Option {
+	return func(o *options) {
+		o.keepAlive = period
+	}
+}
+
+// WithReconnectBackoff sets time to wait before dial after dial failure of persistent connection.
+// It starts from min and is doubled for each failure up to max. default is RECONNECT_MIN_BACKOFF and RECONNECT_MAX_BACKOFF.
+func WithReconnectBackoff(min, max time.Duration) Option {
+	return func(o *options) {
+		o.minBackoff = min
+		o.maxBackoff = max
+	}
+}
+
+// WithConnEventHandler sets handler that is called when state of persistent connection changes.
+// handler is called in order of events, on the goroutine of the request after its response, or the reader of the connection.
+// handler can send request, but it should return quickly because next events wait it.
+func WithConnEventHandler(handler func(ConnEvent)) Option {
+	return func(o *options) {
+		o.onConnEvent = handler
+	}
+}
+
+// WithMaxInFlight sets maximum number of requests that are sent and wait response at the same time. default is DEFAULT_MAX_IN_FLIGHT.
+// Requests over the maximum wait in queue in order of priority. see ContextWithPriority.
+// 4E frame tcp client sends requests on one connection without waiting response, and checks serial number of each response.
+// 3E frame tcp client sends next request after response, so its maximum is always 1.
+// udp client and 1E frame client use own socket for each in-flight request.
+func WithMaxInFlight(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxInFlight = n
+		}
+	}
+}
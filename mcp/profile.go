@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Series is PLC CPU series.
+type Series int
+
+const (
+	// SeriesQ is MELSEC-Q series.
+	SeriesQ Series = iota
+	// SeriesL is MELSEC-L series.
+	SeriesL
+	// SeriesIQR is MELSEC iQ-R series.
+	SeriesIQR
+	// SeriesIQF is MELSEC iQ-F series (FX5).
+	SeriesIQF
+	// SeriesFX is MELSEC-F series like FX3U that is accessed by 1E frame of FX3U-ENET.
+	SeriesFX
+)
+
+func (s Series) String() string {
+	switch s {
+	case SeriesQ:
+		return "MELSEC-Q"
+	case SeriesL:
+		return "MELSEC-L"
+	case SeriesIQR:
+		return "MELSEC iQ-R"
+	case SeriesIQF:
+		return "MELSEC iQ-F"
+	case SeriesFX:
+		return "MELSEC-F"
+	}
+	return fmt.Sprintf("Series(%d)", int(s))
+}
+
+// Profile is communication settings and limits that depend on PLC CPU series.
+type Profile struct {
+	// Series is PLC CPU series.
+	Series Series
+	// Addressing is device specification layout.
+	Addressing Addressing
+	// MaxWordPoints is maximum number of points of batch read and write in word units.
+	MaxWordPoints int64
+	// MaxBitPoints is maximum number of points of batch read and write in bit units.
+	MaxBitPoints int64
+}
+
+// unsupportedDevices is devices that CPU of each series does not have.
+// iQ-R only devices of MELSEC-Q/L are rejected by QAddressing.
+var unsupportedDevices = map[Series]map[string]bool{
+	SeriesIQF: {
+		"ZR":   true,
+		"DX":   true,
+		"DY":   true,
+		"LTS":  true,
+		"LTC":  true,
+		"LTN":  true,
+		"LSTS": true,
+		"LSTC": true,
+		"LSTN": true,
+	},
+}
+
+// ProfileOf returns default profile of series.
+// MELSECコミュニケーションプロトコル リファレンス 一括読出し: 960[word], 7168[bit]
+func ProfileOf(series Series) Profile {
+	p := Profile{
+		Series:        series,
+		Addressing:    QAddressing,
+		MaxWordPoints: 960,
+		MaxBitPoints:  7168,
+	}
+	switch series {
+	case SeriesIQR:
+		p.Addressing = IQRAddressing
+	case SeriesFX:
+		// 1E frame batch read and write
+		p.MaxWordPoints = 256
+		p.MaxBitPoints = 256
+	}
+	return p
+}
+
+// DetectProfile returns profile of series that CPU model name belongs to.
+// modelName is the result of ReadCPUModel like "Q03UDVCPU", "L02CPU", "R04CPU" or "FX5U-32MR/ES".
+func DetectProfile(modelName string) (Profile, error) {
+	name := strings.TrimSpace(modelName)
+	switch {
+	case strings.HasPrefix(name, "FX5"):
+		return ProfileOf(SeriesIQF), nil
+	case strings.HasPrefix(name, "R"):
+		return ProfileOf(SeriesIQR), nil
+	case strings.HasPrefix(name, "L"):
+		return ProfileOf(SeriesL), nil
+	case strings.HasPrefix(name, "Q"):
+		return ProfileOf(SeriesQ), nil
+	}
+	return Profile{}, fmt.Errorf("series of cpu model %q is unknown", name)
+}
@@ -0,0 +1,31 @@
+package mcp
+
+import "testing"
+
+func TestDetectProfile(t *testing.T) {
+	cases := []struct {
+		modelName string
+		expected  Series
+	}{
+		{"Q03UDVCPU       ", SeriesQ},
+		{"L02CPU", SeriesL},
+		{"R04CPU", SeriesIQR},
+		{"FX5U-32MR/ES", SeriesIQF},
+	}
+	for _, c := range cases {
+		profile, err := DetectProfile(c.modelName)
+		if err != nil {
+			t.Fatalf("unexpected detect err: %v", err)
+		}
+		if profile.Series != c.expected {
+			t.Fatalf("expected %v but actual is %v", c.expected, profile.Series)
+		}
+	}
+
+	if profile, _ := DetectProfile("R04CPU"); profile.Addressing != IQRAddressing {
+		t.Fatalf("expected %v but actual is %v", IQRAddressing, profile.Addressing)
+	}
+	if _, err := DetectProfile("A1SCPU"); err == nil {
+		t.Fatalf("expected error of unknown cpu model but actual is nil")
+	}
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+)
+
+// DEFAULT_MAX_IN_FLIGHT is default maximum number of requests that are sent to plc and wait response at the same time.
+const DEFAULT_MAX_IN_FLIGHT = 1
+
+// Priority is priority of request in request queue of client. request of higher priority is sent first.
+type Priority int
+
+const (
+	// PriorityLow is for background polling.
+	PriorityLow Priority = -1
+	// PriorityNormal is default priority.
+	PriorityNormal Priority = 0
+	// PriorityHigh is for request that should go ahead of polling like write.
+	PriorityHigh Priority = 1
+)
+
+type priorityKey struct{}
+
+// ContextWithPriority returns ctx that has priority of request. Use it with context-aware methods like WriteContext.
+func ContextWithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// priorityOf returns priority of ctx. default is PriorityNormal.
+func priorityOf(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityNormal
+}
+
+// requestQueue limits number of in-flight requests. Requests over the limit wait in order of priority, and then arrival.
+type requestQueue struct {
+	mu sync.Mutex
+	// maximum number of in-flight requests
+	max int
+	// number of in-flight requests
+	inFlight int
+	// waiting requests sorted by priority and arrival
+	waiting []*queuedRequest
+}
+
+// queuedRequest is request waiting in requestQueue. ready is closed when it becomes in-flight.
+type queuedRequest struct {
+	priority Priority
+	ready    chan struct{}
+}
+
+func newRequestQueue(max int) *requestQueue {
+	if max < 1 {
+		max = 1
+	}
+	return &requestQueue{max: max}
+}
+
+// acquire waits until request can be in-flight. If ctx is done while waiting, ctx.Err() is returned.
+// release must be called after response of acquired request.
+func (q *requestQueue) acquire(ctx context.Context, priority Priority) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	if q.inFlight < q.max && len(q.waiting) == 0 {
+		q.inFlight++
+		q.mu.Unlock()
+		return nil
+	}
+
+	// after requests of the same or higher priority
+	r := &queuedRequest{priority: priority, ready: make(chan struct{})}
+	i := len(q.waiting)
+	for i > 0 && q.waiting[i-1].priority < priority {
+		i--
+	}
+	q.waiting = append(q.waiting, nil)
+	copy(q.waiting[i+1:], q.waiting[i:])
+	q.waiting[i] = r
+	q.mu.Unlock()
+
+	select {
+	case <-r.ready:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		for i, w := range q.waiting {
+			if w == r {
+				q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+				q.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		q.mu.Unlock()
+
+		// request became in-flight at the same time
+		q.release()
+		return ctx.Err()
+	}
+}
+
+// release ends in-flight request, and makes the first waiting request in-flight.
+func (q *requestQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.waiting) > 0 {
+		r := q.waiting[0]
+		q.waiting = q.waiting[1:]
+		close(r.ready)
+		return
+	}
+	q.inFlight--
+}
+
+// queuedTransport is transport that sends requests through requestQueue.
+// It is used for transport that does not hold connection, because each request has own connection or socket.
+type queuedTransport struct {
+	transport
+	queue *requestQueue
+}
+
+func (t *queuedTransport) roundTrip(ctx context.Context, payload []byte, readSize int64) ([]byte, error) {
+	resps, err := t.roundTrips(ctx, [][]byte{payload}, []int64{readSize})
+	if err != nil {
+		return nil, err
+	}
+	return resps[0], nil
+}
+
+func (t *queuedTransport) roundTrips(ctx context.Context, payloads [][]byte, readSizes []int64) ([][]byte, error) {
+	if err := t.queue.acquire(ctx, priorityOf(ctx)); err != nil {
+		return nil, err
+	}
+	defer t.queue.release()
+	return t.transport.roundTrips(ctx, payloads, readSizes)
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// waitQueued waits until n requests are waiting in q.
+func waitQueued(t *testing.T, q *requestQueue, n int) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		q.mu.Lock()
+		waiting := len(q.waiting)
+		q.mu.Unlock()
+		if waiting == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %v waiting requests but they are not queued", n)
+}
+
+func TestRequestQueue_Priority(t *testing.T) {
+	q := newRequestQueue(1)
+	if err := q.acquire(context.Background(), PriorityNormal); err != nil {
+		t.Fatalf("unexpected acquire err: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	requests := []struct {
+		name     string
+		priority Priority
+	}{
+		{"poll1", PriorityLow},
+		{"read1", PriorityNormal},
+		{"poll2", PriorityLow},
+		{"write1", PriorityHigh},
+		{"read2", PriorityNormal},
+		{"write2", PriorityHigh},
+	}
+	for i, r := range requests {
+		wg.Add(1)
+		go func(name string, priority Priority) {
+			defer wg.Done()
+			if err := q.acquire(context.Background(), priority); err != nil {
+				t.Errorf("unexpected acquire err: %v", err)
+				return
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			q.release()
+		}(r.name, r.priority)
+		// keep arrival order
+		waitQueued(t, q, i+1)
+	}
+
+	q.release()
+	wg.Wait()
+
+	expected := []string{"write1", "write2", "read1", "read2", "poll1", "poll2"}
+	if diff := cmp.Diff(expected, order); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+	if q.inFlight != 0 {
+		t.Fatalf("expected %v but actual is %v", 0, q.inFlight)
+	}
+}
+
+func TestRequestQueue_MaxInFlight(t *testing.T) {
+	q := newRequestQueue(2)
+	for i := 0; i < 2; i++ {
+		if err := q.acquire(context.Background(), PriorityNormal); err != nil {
+			t.Fatalf("unexpected acquire err: %v", err)
+		}
+	}
+
+	// 3rd request waits until ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := q.acquire(ctx, PriorityHigh); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v but actual is %v", context.DeadlineExceeded, err)
+	}
+	if len(q.waiting) != 0 {
+		t.Fatalf("expected canceled request is removed but actual is %v waiting", len(q.waiting))
+	}
+
+	q.release()
+	if err := q.acquire(context.Background(), PriorityNormal); err != nil {
+		t.Fatalf("unexpected acquire err: %v", err)
+	}
+	if q.inFlight != 2 {
+		t.Fatalf("expected %v but actual is %v", 2, q.inFlight)
+	}
+}
+
+func TestPriorityOf(t *testing.T) {
+	if actual := priorityOf(context.Background()); actual != PriorityNormal {
+		t.Errorf("expected %v but actual is %v", PriorityNormal, actual)
+	}
+	if actual := priorityOf(ContextWithPriority(context.Background(), PriorityHigh)); actual != PriorityHigh {
+		t.Errorf("expected %v but actual is %v", PriorityHigh, actual)
+	}
+}
@@ -5,6 +5,16 @@ import (
 	"fmt"
 )
 
+const (
+	RESP_SUB_HEADER    = "D000" // 3Eフレームのレスポンスでは固定
+	RESP_SUB_HEADER_4E = "D400" // 4Eフレームのレスポンスでは固定
+
+	END_CODE_NORMAL = "0000" // 3E, 4Eフレームの正常終了
+
+	END_CODE_1E_NORMAL   = "00" // 1Eフレームの正常終了
+	END_CODE_1E_ABNORMAL = "5B" // 1Eフレームの異常終了. 異常コードが続く
+)
+
 type parser struct {
 }
 
@@ -16,6 +26,8 @@ func NewParser() *parser {
 type Response struct {
 	// Sub header
 	SubHeader string
+	// Serial number. only 4E frame
+	SerialNum string
 	// network number
 	NetworkNum string
 	// PC number
@@ -28,34 +40,130 @@ type Response struct {
 	DataLen string
 	// Response data code
 	EndCode string
-	// Response data
+	// Response data. In ascii code, it is characters of response data
 	Payload []byte
-	// error data
+	// error data. error information of 3E and 4E frame, or abnormal code of 1E frame
 	ErrInfo []byte
 }
 
+// Do parses 3E or 4E frame response. frame type is decided by sub header.
+// Ascii code response is also parsed. header fields of ascii code response are characters of response as it is,
+// and Payload is characters of response data.
 func (p *parser) Do(resp []byte) (*Response, error) {
-	if len(resp) < 11 {
-		return nil, errors.New("length must be larger than 22 byte")
-	}
-
-	subHeaderB := resp[0:2]
-	networkNumB := resp[2:3]
-	pcNumB := resp[3:4]
-	unitIONumB := resp[4:6]
-	unitStationNumB := resp[6:7]
-	dataLenB := resp[7:9]
-	endCodeB := resp[9:11]
-	payloadB := resp[11:]
-
-	return &Response{
-		SubHeader:      fmt.Sprintf("%X", subHeaderB),
-		NetworkNum:     fmt.Sprintf("%X", networkNumB),
-		PCNum:          fmt.Sprintf("%X", pcNumB),
-		UnitIONum:      fmt.Sprintf("%X", unitIONumB),
-		UnitStationNum: fmt.Sprintf("%X", unitStationNumB),
-		DataLen:        fmt.Sprintf("%X", dataLenB),
-		EndCode:        fmt.Sprintf("%X", endCodeB),
-		Payload:        payloadB,
-	}, nil
+	code := Binary
+	if len(resp) > 0 && resp[0] == RESP_SUB_HEADER[0] {
+		// binary response starts with 0xD0, ascii response starts with 'D'
+		code = Ascii
+	}
+
+	frame4E := false
+	if code == Ascii && len(resp) >= 4 && string(resp[0:4]) == RESP_SUB_HEADER_4E {
+		frame4E = true
+	}
+	if code == Binary && len(resp) >= 2 && fmt.Sprintf("%X", resp[0:2]) == RESP_SUB_HEADER_4E {
+		frame4E = true
+	}
+
+	return p.parse(resp, code, frame4E)
+}
+
+// Do4E parses 4E frame response and checks that serial number of response is same as request.
+func (p *parser) Do4E(resp []byte, serialNum uint16) (*Response, error) {
+	response, err := p.Do(resp)
+	if err != nil {
+		return nil, err
+	}
+	if response.SubHeader != RESP_SUB_HEADER_4E {
+		return nil, errors.New("sub header is not 4E frame response: " + response.SubHeader)
+	}
+
+	// binary: little endian, ascii: big endian
+	expected := Binary.uintField(int64(serialNum), 2)
+	if resp[0] == RESP_SUB_HEADER[0] {
+		expected = Ascii.uintField(int64(serialNum), 2)
+	}
+	if response.SerialNum != expected {
+		return nil, fmt.Errorf("serial number is mismatched: expected %v but actual is %v", expected, response.SerialNum)
+	}
+	return response, nil
+}
+
+// parse parses 3E or 4E frame response.
+// 4E frame response has serial number[2byte] and fixed value 0000[2byte] after sub header D400.
+// In ascii code, each field is twice as long as binary code.
+func (p *parser) parse(resp []byte, code Code, frame4E bool) (*Response, error) {
+	// 1byte=2char in ascii code
+	width := 1
+	if code == Ascii {
+		width = 2
+	}
+
+	headerLen := 11 * width
+	if frame4E {
+		headerLen += 4 * width
+	}
+	if len(resp) < headerLen {
+		return nil, fmt.Errorf("length must be larger than %v byte", headerLen)
+	}
+
+	// field returns next size byte field
+	pos := 0
+	field := func(size int) string {
+		b := resp[pos : pos+size*width]
+		pos += size * width
+		if code == Ascii {
+			return string(b)
+		}
+		return fmt.Sprintf("%X", b)
+	}
+
+	response := &Response{}
+	response.SubHeader = field(2)
+	if frame4E {
+		response.SerialNum = field(2)
+		_ = field(2) // 固定値
+	}
+	response.NetworkNum = field(1)
+	response.PCNum = field(1)
+	response.UnitIONum = field(2)
+	response.UnitStationNum = field(1)
+	response.DataLen = field(2)
+	response.EndCode = field(2)
+
+	// error information follows error end code instead of response data
+	if response.EndCode == END_CODE_NORMAL {
+		response.Payload = resp[pos:]
+	} else {
+		response.ErrInfo = resp[pos:]
+	}
+
+	return response, nil
+}
+
+// Do1E parses 1E frame response.
+// 1E frame response is sub header[1byte], completion code[1byte] and data.
+// If completion code is 5B, abnormal code[1byte] follows completion code and it is stored in ErrInfo.
+func (p *parser) Do1E(resp []byte) (*Response, error) {
+	if len(resp) < 2 {
+		return nil, errors.New("length must be larger than 2 byte")
+	}
+
+	subHeaderB := resp[0:1]
+	endCodeB := resp[1:2]
+
+	response := &Response{
+		SubHeader: fmt.Sprintf("%X", subHeaderB),
+		EndCode:   fmt.Sprintf("%X", endCodeB),
+	}
+
+	switch response.EndCode {
+	case END_CODE_1E_NORMAL:
+		response.Payload = resp[2:]
+	case END_CODE_1E_ABNORMAL:
+		if len(resp) < 3 {
+			return nil, errors.New("abnormal code is not found in response: " + fmt.Sprintf("%X", resp))
+		}
+		response.ErrInfo = resp[2:3]
+	}
+	return response, nil
 }
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"encoding/hex"
+	"fmt"
 	"github.com/google/go-cmp/cmp"
 	"testing"
 )
@@ -31,3 +32,134 @@ func TestParser_Do(t *testing.T) {
 		t.Errorf("parse Resp differs: (-got +want)\n%s", diff)
 	}
 }
+
+func TestParser_Do4E(t *testing.T) {
+	mcResp, _ := hex.DecodeString("d4003412000000ffff0300040000000000")
+
+	p := NewParser()
+	response, err := p.Do4E(mcResp, 0x1234)
+	if err != nil {
+		t.Fatalf("unexpected parser err: %v", err)
+	}
+
+	expected := &Response{
+		SubHeader:      "D400",
+		SerialNum:      "3412",
+		NetworkNum:     "00",
+		PCNum:          "FF",
+		UnitIONum:      "FF03",
+		UnitStationNum: "00",
+		DataLen:        "0400",
+		EndCode:        "0000",
+		Payload:        []uint8{0x00, 0x00},
+		ErrInfo:        nil,
+	}
+
+	if diff := cmp.Diff(response, expected); diff != "" {
+		t.Errorf("parse Resp differs: (-got +want)\n%s", diff)
+	}
+
+	// Do detects 4E frame by sub header
+	response2, err := p.Do(mcResp)
+	if err != nil {
+		t.Fatalf("unexpected parser err: %v", err)
+	}
+	if diff := cmp.Diff(response2, expected); diff != "" {
+		t.Errorf("parse Resp differs: (-got +want)\n%s", diff)
+	}
+
+	// serial number mismatch
+	if _, err := p.Do4E(mcResp, 0x1235); err == nil {
+		t.Fatalf("expected serial number mismatch error but actual is nil")
+	}
+}
+
+func TestParser_Do1E(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected *Response
+	}{
+		{
+			input: "8100341202000300",
+			expected: &Response{
+				SubHeader: "81",
+				EndCode:   "00",
+				Payload:   []uint8{0x34, 0x12, 0x02, 0x00, 0x03, 0x00},
+			},
+		},
+		{
+			input: "835B10",
+			expected: &Response{
+				SubHeader: "83",
+				EndCode:   "5B",
+				ErrInfo:   []uint8{0x10},
+			},
+		},
+	}
+
+	p := NewParser()
+	for _, v := range cases {
+		mcResp, _ := hex.DecodeString(v.input)
+		response, err := p.Do1E(mcResp)
+		if err != nil {
+			t.Fatalf("unexpected parser err: %v", err)
+		}
+
+		if diff := cmp.Diff(response, v.expected); diff != "" {
+			t.Errorf("parse Resp differs: (-got +want)\n%s", diff)
+		}
+	}
+}
+
+func TestParser_DoAscii(t *testing.T) {
+	p := NewParser()
+	response, err := p.Do([]byte("D00000FF03FF00000C000012340002"))
+	if err != nil {
+		t.Fatalf("unexpected parser err: %v", err)
+	}
+
+	expected := &Response{
+		SubHeader:      "D000",
+		NetworkNum:     "00",
+		PCNum:          "FF",
+		UnitIONum:      "03FF",
+		UnitStationNum: "00",
+		DataLen:        "000C",
+		EndCode:        "0000",
+		Payload:        []byte("12340002"),
+	}
+
+	if diff := cmp.Diff(response, expected); diff != "" {
+		t.Errorf("parse Resp differs: (-got +want)\n%s", diff)
+	}
+
+	// 4E frame
+	response2, err := p.Do4E([]byte("D4001234000000FF03FF00000C000012340002"), 0x1234)
+	if err != nil {
+		t.Fatalf("unexpected parser err: %v", err)
+	}
+	expected.SubHeader = "D400"
+	expected.SerialNum = "1234"
+	if diff := cmp.Diff(response2, expected); diff != "" {
+		t.Errorf("parse Resp differs: (-got +want)\n%s", diff)
+	}
+}
+
+// checkDataLen checks that response data length in header is same as length of end code and response data or error information.
+// frame reader already reads response by the length, so it is used to check responses built or rewritten in tests.
+func checkDataLen(response *Response, code Code) error {
+	dataLen, err := code.fieldValue(response.DataLen)
+	if err != nil {
+		return fmt.Errorf("invalid response data length %v: %v", response.DataLen, err)
+	}
+
+	// end code is 2byte. 4char in ascii
+	endCodeLen := 2
+	if code == Ascii {
+		endCodeLen = 4
+	}
+	if actual := endCodeLen + len(response.Payload) + len(response.ErrInfo); int(dataLen) != actual {
+		return fmt.Errorf("response data length is %v but actual is %v", dataLen, actual)
+	}
+	return nil
+}
@@ -1,13 +1,10 @@
 package mcp
 
-import (
-	"bytes"
-	"encoding/binary"
-	"fmt"
-)
+import "fmt"
 
 const (
-	SUB_HEADER = "5000" // 3Eフレームでは固定
+	SUB_HEADER    = "5000" // 3Eフレームでは固定
+	SUB_HEADER_4E = "5400" // 4Eフレームでは固定. シリアル番号と固定値0000が続く
 
 	HEALTH_CHECK_COMMAND    = "1906" // binary mode expression. if ascii mode then 0619
 	HEALTH_CHECK_SUBCOMMAND = "0000"
@@ -16,24 +13,45 @@ const (
 	READ_SUB_COMMAND     = "0000"
 	BIT_READ_SUB_COMMAND = "0100"
 
-	WRITE_COMMAND     = "0114" // binary mode expression. if ascii mode then 1401
-	WRITE_SUB_COMMAND = "0000"
+	WRITE_COMMAND         = "0114" // binary mode expression. if ascii mode then 1401
+	WRITE_SUB_COMMAND     = "0000"
+	BIT_WRITE_SUB_COMMAND = "0100"
 
-	MONITORING_TIMER = "1000" // 3[sec]
-)
+	RANDOM_READ_COMMAND     = "0304" // binary mode expression. if ascii mode then 0403
+	RANDOM_READ_SUB_COMMAND = "0000"
 
-// deviceCodes is device name and hex value map
-var deviceCodes = map[string]string{
-	"X": "9C",
-	"Y": "9D",
-	"M": "90",
-	"L": "92",
-	"F": "93",
-	"V": "94",
-	"B": "A0",
-	"W": "B4",
-	"D": "A8",
-}
+	RANDOM_WRITE_COMMAND         = "0214" // binary mode expression. if ascii mode then 1402
+	RANDOM_WRITE_SUB_COMMAND     = "0000"
+	RANDOM_BIT_WRITE_SUB_COMMAND = "0100"
+
+	BLOCK_READ_COMMAND  = "0604" // binary mode expression. if ascii mode then 0406
+	BLOCK_WRITE_COMMAND = "0614" // binary mode expression. if ascii mode then 1406
+	BLOCK_SUB_COMMAND   = "0000"
+
+	ENTRY_MONITOR_COMMAND     = "0108" // binary mode expression. if ascii mode then 0801
+	ENTRY_MONITOR_SUB_COMMAND = "0000"
+	MONITOR_COMMAND           = "0208" // binary mode expression. if ascii mode then 0802
+	MONITOR_SUB_COMMAND       = "0000"
+
+	REMOTE_RUN_COMMAND         = "0110" // binary mode expression. if ascii mode then 1001
+	REMOTE_STOP_COMMAND        = "0210" // binary mode expression. if ascii mode then 1002
+	REMOTE_PAUSE_COMMAND       = "0310" // binary mode expression. if ascii mode then 1003
+	REMOTE_LATCH_CLEAR_COMMAND = "0510" // binary mode expression. if ascii mode then 1005
+	REMOTE_RESET_COMMAND       = "0610" // binary mode expression. if ascii mode then 1006
+	REMOTE_SUB_COMMAND         = "0000"
+
+	// 0613/1613 accesses buffer memory of intelligent function module. 0601/1601 is for the connected ethernet module itself.
+	MODULE_BUFFER_READ_COMMAND  = "1306" // binary mode expression. if ascii mode then 0613
+	MODULE_BUFFER_WRITE_COMMAND = "1316" // binary mode expression. if ascii mode then 1613
+	MODULE_BUFFER_SUB_COMMAND   = "0000"
+
+	CPU_MODEL_READ_COMMAND     = "0101" // binary mode expression. if ascii mode then 0101
+	CPU_MODEL_READ_SUB_COMMAND = "0000"
+
+	MONITORING_TIMER = "1000" // 250[msec] * 16 = 4[sec]. binary mode expression. if ascii mode then 0010
+
+	HEALTH_CHECK_DATA = "ABCDE" // 折返しデータ
+)
 
 // Each single PLC that is connected on MELSECNET and CC-Link IE is called a station.
 type station struct {
@@ -45,14 +63,22 @@ type station struct {
 	unitIONum string
 	// PLC stn Unit Station Number
 	unitStationNum string
+	// data communication code
+	code Code
+	// device specification layout
+	addressing Addressing
+	// PLC CPU series
+	series Series
 }
 
+// NewStation returns station. each number is binary mode expression like FF03.
 func NewStation(networkNum, pcNum, unitIONum, unitStationNum string) *station {
 	return &station{
 		networkNum:     networkNum,
 		pcNum:          pcNum,
 		unitIONum:      unitIONum,
 		unitStationNum: unitStationNum,
+		code:           Binary,
 	}
 }
 
@@ -63,110 +89,75 @@ func NewLocalStation() *station {
 		pcNum:          "FF",   // 自局の場合はFF固定
 		unitIONum:      "FF03", // マルチドロップ接続などでない場合はFF03固定値
 		unitStationNum: "00",   // マルチドロップ接続などでない場合は00固定値
+		code:           Binary,
 	}
 }
 
 func (h *station) BuildHealthCheckRequest() string {
 
-	returnDataNum := "0500"    // 5 device. if ascii mode then 0005
-	returnData := "4142434445" // value is "ABCDE".
+	returnDataNum := h.code.uintField(int64(len(HEALTH_CHECK_DATA)), 2) // 5 device. binary: 0500, ascii: 0005
+	returnData := h.code.stringField(HEALTH_CHECK_DATA)                 // value is "ABCDE".
 
-	requestStr := HEALTH_CHECK_COMMAND + HEALTH_CHECK_SUBCOMMAND + returnDataNum + returnData
+	requestStr := h.code.layout(HEALTH_CHECK_COMMAND) +
+		h.code.layout(HEALTH_CHECK_SUBCOMMAND) +
+		returnDataNum +
+		returnData
 
-	// data length
-	requestCharLen := len(MONITORING_TIMER+requestStr) / 2 // 1byte=2char
-	dataLenBuff := new(bytes.Buffer)
-	_ = binary.Write(dataLenBuff, binary.LittleEndian, int64(requestCharLen))
-	dataLen := fmt.Sprintf("%X", dataLenBuff.Bytes()[0:2]) // 2byte固定
+	return h.buildFrame(requestStr)
+}
 
-	return SUB_HEADER +
-		h.networkNum +
-		h.pcNum +
-		h.unitIONum +
-		h.unitStationNum +
-		dataLen +
-		MONITORING_TIMER +
-		requestStr
+// BuildReadCPUModelRequest represents MCP CPU model name read command.
+func (h *station) BuildReadCPUModelRequest() string {
+	requestStr := h.code.layout(CPU_MODEL_READ_COMMAND) +
+		h.code.layout(CPU_MODEL_READ_SUB_COMMAND)
+
+	return h.buildFrame(requestStr)
 }
 
 // BuildReadRequest represents MCP read as word command.
 // deviceName is device code name like 'D' register.
 // offset is device offset addr.
 // numPoints is number of read device points.
-func (h *station) BuildReadRequest(deviceName string, offset, numPoints int64) string {
-
-	// get device symbol hex layout
-	deviceCode := deviceCodes[deviceName]
-
-	// offset convert to little endian layout
-	// MELSECコミュニケーションプロトコル リファレンス(p67) MELSEC-Q/L: 3[byte], MELSEC iQ-R: 4[byte]
-	offsetBuff := new(bytes.Buffer)
-	_ = binary.Write(offsetBuff, binary.LittleEndian, offset)
-	offsetHex := fmt.Sprintf("%X", offsetBuff.Bytes()[0:3]) // 仮にQシリーズとするので3byte trim
+func (h *station) BuildReadRequest(deviceName string, offset, numPoints int64) (string, error) {
+	if err := h.validateExtendedDevice(deviceName); err != nil {
+		return "", err
+	}
+	spec, err := h.deviceSpec(READ_SUB_COMMAND, deviceName, offset)
+	if err != nil {
+		return "", err
+	}
 
 	// read points
-	pointsBuff := new(bytes.Buffer)
-	_ = binary.Write(pointsBuff, binary.LittleEndian, numPoints)
-	points := fmt.Sprintf("%X", pointsBuff.Bytes()[0:2]) // 2byte固定
+	points := h.code.uintField(numPoints, 2) // 2byte固定
 
-	// data length
-	requestCharLen := len(MONITORING_TIMER+READ_COMMAND+READ_SUB_COMMAND+deviceCode+offsetHex+points) / 2 // 1byte=2char
-	dataLenBuff := new(bytes.Buffer)
-	_ = binary.Write(dataLenBuff, binary.LittleEndian, int64(requestCharLen))
-	dataLen := fmt.Sprintf("%X", dataLenBuff.Bytes()[0:2]) // 2byte固定
-
-	return SUB_HEADER +
-		h.networkNum +
-		h.pcNum +
-		h.unitIONum +
-		h.unitStationNum +
-		dataLen +
-		MONITORING_TIMER +
-		READ_COMMAND +
-		READ_SUB_COMMAND +
-		offsetHex +
-		deviceCode +
+	requestStr := h.code.layout(READ_COMMAND) +
+		spec +
 		points
+
+	return h.buildFrame(requestStr), nil
 }
 
 // BuildReadRequest represents MCP read as bit command.
 // deviceName is device code name like 'D' register.
 // offset is device offset addr.
 // numPoints is number of read device points.
-func (h *station) BuildBitReadRequest(deviceName string, offset, numPoints int64) string {
-
-	// get device symbol hex layout
-	deviceCode := deviceCodes[deviceName]
-
-	// offset convert to little endian layout
-	// MELSECコミュニケーションプロトコル リファレンス(p67) MELSEC-Q/L: 3[byte], MELSEC iQ-R: 4[byte]
-	offsetBuff := new(bytes.Buffer)
-	_ = binary.Write(offsetBuff, binary.LittleEndian, offset)
-	offsetHex := fmt.Sprintf("%X", offsetBuff.Bytes()[0:3]) // 仮にQシリーズとするので3byte trim
+func (h *station) BuildBitReadRequest(deviceName string, offset, numPoints int64) (string, error) {
+	if err := h.validateExtendedDevice(deviceName); err != nil {
+		return "", err
+	}
+	spec, err := h.deviceSpec(BIT_READ_SUB_COMMAND, deviceName, offset)
+	if err != nil {
+		return "", err
+	}
 
 	// read points
-	pointsBuff := new(bytes.Buffer)
-	_ = binary.Write(pointsBuff, binary.LittleEndian, numPoints)
-	points := fmt.Sprintf("%X", pointsBuff.Bytes()[0:2]) // 2byte固定
-
-	// data length
-	requestCharLen := len(MONITORING_TIMER+READ_COMMAND+BIT_READ_SUB_COMMAND+deviceCode+offsetHex+points) / 2 // 1byte=2char
-	dataLenBuff := new(bytes.Buffer)
-	_ = binary.Write(dataLenBuff, binary.LittleEndian, int64(requestCharLen))
-	dataLen := fmt.Sprintf("%X", dataLenBuff.Bytes()[0:2]) // 2byte固定
+	points := h.code.uintField(numPoints, 2) // 2byte固定
 
-	return SUB_HEADER +
-		h.networkNum +
-		h.pcNum +
-		h.unitIONum +
-		h.unitStationNum +
-		dataLen +
-		MONITORING_TIMER +
-		READ_COMMAND +
-		BIT_READ_SUB_COMMAND +
-		offsetHex +
-		deviceCode +
+	requestStr := h.code.layout(READ_COMMAND) +
+		spec +
 		points
+
+	return h.buildFrame(requestStr), nil
 }
 
 // BuildWriteRequest represents MCP write command.
@@ -176,45 +167,188 @@ func (h *station) BuildBitReadRequest(deviceName string, offset, numPoints int64
 // numPoints is number of write device points.
 // writeData is the data to be written. If writeData is larger than 2*numPoints bytes,
 // data larger than 2*numPoints bytes is ignored.
-func (h *station) BuildWriteRequest(deviceName string, offset, numPoints int64, writeData []byte) string {
+func (h *station) BuildWriteRequest(deviceName string, offset, numPoints int64, writeData []byte) (string, error) {
+	if err := h.validateExtendedDevice(deviceName); err != nil {
+		return "", err
+	}
+	spec, err := h.deviceSpec(WRITE_SUB_COMMAND, deviceName, offset)
+	if err != nil {
+		return "", err
+	}
 
-	// get device symbol hex layout
-	deviceCode := deviceCodes[deviceName]
+	// write points
+	points := h.code.uintField(numPoints, 2) // 2byte固定
 
-	// offset convert to little endian layout
-	// MELSECコミュニケーションプロトコル リファレンス(p67) MELSEC-Q/L: 3[byte], MELSEC iQ-R: 4[byte]
-	offsetBuff := new(bytes.Buffer)
-	_ = binary.Write(offsetBuff, binary.LittleEndian, offset)
-	offsetHex := fmt.Sprintf("%X", offsetBuff.Bytes()[0:3]) // 仮にQシリーズとするので3byte trim
+	requestStr := h.code.layout(WRITE_COMMAND) +
+		spec +
+		points +
+		h.code.wordsField(writeData[0:2*numPoints]) // 2 byte per 1 device point
 
-	// convert write data to little endian word
-	writeBuff := new(bytes.Buffer)
-	_ = binary.Write(writeBuff, binary.LittleEndian, writeData)
-	writeHex := fmt.Sprintf("%X", writeBuff.Bytes()[0:2*numPoints]) // 2 byte per 1 device point
+	return h.buildFrame(requestStr), nil
+}
+
+// BuildBitWriteRequest represents MCP write as bit command.
+// deviceName is device code name like 'M' relay.
+// offset is device offset addr.
+// values are written from offset. number of write device points is len(values).
+func (h *station) BuildBitWriteRequest(deviceName string, offset int64, values []bool) (string, error) {
+	if err := h.validateExtendedDevice(deviceName); err != nil {
+		return "", err
+	}
+	spec, err := h.deviceSpec(BIT_WRITE_SUB_COMMAND, deviceName, offset)
+	if err != nil {
+		return "", err
+	}
 
 	// write points
-	pointsBuff := new(bytes.Buffer)
-	_ = binary.Write(pointsBuff, binary.LittleEndian, numPoints)
-	points := fmt.Sprintf("%X", pointsBuff.Bytes()[0:2]) // 2byte固定
+	points := h.code.uintField(int64(len(values)), 2) // 2byte固定
+
+	requestStr := h.code.layout(WRITE_COMMAND) +
+		spec +
+		points +
+		h.code.bitsField(values)
+
+	return h.buildFrame(requestStr), nil
+}
+
+// BuildReadDeviceRequest is BuildReadRequest by device address like ParseDevice("D100").
+func (h *station) BuildReadDeviceRequest(device Device, numPoints int64) (string, error) {
+	if err := validateBatchDevice(device); err != nil {
+		return "", err
+	}
+	return h.BuildReadRequest(device.Name, device.Offset, numPoints)
+}
+
+// BuildBitReadDeviceRequest is BuildBitReadRequest by device address like ParseDevice("X1F").
+func (h *station) BuildBitReadDeviceRequest(device Device, numPoints int64) (string, error) {
+	if err := validateBatchDevice(device); err != nil {
+		return "", err
+	}
+	return h.BuildBitReadRequest(device.Name, device.Offset, numPoints)
+}
+
+// BuildWriteDeviceRequest is BuildWriteRequest by device address like ParseDevice("D100").
+func (h *station) BuildWriteDeviceRequest(device Device, numPoints int64, writeData []byte) (string, error) {
+	if err := validateBatchDevice(device); err != nil {
+		return "", err
+	}
+	return h.BuildWriteRequest(device.Name, device.Offset, numPoints, writeData)
+}
+
+// BuildBitWriteDeviceRequest is BuildBitWriteRequest by device address like ParseDevice("M100").
+func (h *station) BuildBitWriteDeviceRequest(device Device, values []bool) (string, error) {
+	if err := validateBatchDevice(device); err != nil {
+		return "", err
+	}
+	return h.BuildBitWriteRequest(device.Name, device.Offset, values)
+}
+
+// validateBatchDevice returns error if device is bit of word device like D100.F,
+// because batch read and write command can not specify bit of word device.
+func validateBatchDevice(device Device) error {
+	if device.HasBit {
+		return fmt.Errorf("bit of word device %v can not be specified in batch read and write request. specify the word", device)
+	}
+	return nil
+}
+
+// buildFrame builds 3E frame request. requestStr is command, sub command and request data.
+func (h *station) buildFrame(requestStr string) string {
+	timer := h.code.layout(MONITORING_TIMER)
 
 	// data length
-	requestCharLen := len(MONITORING_TIMER+WRITE_COMMAND+WRITE_SUB_COMMAND+deviceCode+offsetHex+points+writeHex) / 2 // 1byte=2char
-	dataLenBuff := new(bytes.Buffer)
-	_ = binary.Write(dataLenBuff, binary.LittleEndian, int64(requestCharLen))
-	dataLen := fmt.Sprintf("%X", dataLenBuff.Bytes()[0:2]) // 2byte固定
+	dataLen := h.code.uintField(h.code.byteLen(timer+requestStr), 2) // 2byte固定
+
 	return SUB_HEADER +
-		h.networkNum +
-		h.pcNum +
-		h.unitIONum +
-		h.unitStationNum +
+		h.code.layout(h.networkNum) +
+		h.code.layout(h.pcNum) +
+		h.code.layout(h.unitIONum) +
+		h.code.layout(h.unitStationNum) +
 		dataLen +
-		MONITORING_TIMER +
-		WRITE_COMMAND +
-		WRITE_SUB_COMMAND +
-		offsetHex +
-		deviceCode +
-		points +
-		writeHex
+		timer +
+		requestStr
+}
+
+// deviceField returns device number and device code.
+// binary mode is device number[3byte] and device code[1byte],
+// ascii mode is device code[2char] and device number[6char].
+// In IQRAddressing, binary mode is device number[4byte] and device code[2byte],
+// ascii mode is device code[4char] and device number[10char].
+func (h *station) deviceField(deviceName string, offset int64) string {
+	device := deviceCatalogue[deviceName]
+
+	if h.code == Ascii {
+		deviceCode := device.ASCIICode
+		numDigits := 6
+		if h.addressing == IQRAddressing {
+			deviceCode = device.IQRASCIICode // device code is padded with '*' like D***
+			numDigits = 10
+		}
+
+		switch device.numbering(h.series) {
+		case Hexadecimal:
+			return deviceCode + fmt.Sprintf("%0*X", numDigits, offset)
+		case Octal:
+			return deviceCode + fmt.Sprintf("%0*o", numDigits, offset)
+		}
+		// In MELSEC-Q/L, device number of ZR is also expressed as hex.
+		if deviceName == "ZR" && h.addressing == QAddressing {
+			return deviceCode + fmt.Sprintf("%0*X", numDigits, offset)
+		}
+		return deviceCode + fmt.Sprintf("%0*d", numDigits, offset)
+	}
+
+	// get device symbol hex layout
+	deviceCode := fmt.Sprintf("%02X", device.Code)
+
+	// offset convert to little endian layout
+	// MELSECコミュニケーションプロトコル リファレンス(p67) MELSEC-Q/L: 3[byte], MELSEC iQ-R: 4[byte]
+	if h.addressing == IQRAddressing {
+		return h.code.uintField(offset, 4) + deviceCode + "00" // device code is 2byte
+	}
+	offsetHex := h.code.uintField(offset, 3) // Qシリーズは3byte trim
+
+	return offsetHex + deviceCode
+}
+
+// validateDevice checks that device can be accessed by addressing of this station.
+func (h *station) validateDevice(deviceName string) error {
+	device, ok := deviceCatalogue[deviceName]
+	if !ok || extensionOnlyDevices[deviceName] {
+		return fmt.Errorf("device %v is not supported", deviceName)
+	}
+	if device.IQROnly && h.addressing != IQRAddressing {
+		return fmt.Errorf("device %v can be accessed only by MELSEC iQ-R addressing", deviceName)
+	}
+	if unsupportedDevices[h.series][deviceName] {
+		return fmt.Errorf("device %v is not supported by %v", deviceName, h.series)
+	}
+	return nil
+}
+
+// validateDevices checks all devices by validateDevice. bit of word device like D100.F can not be accessed.
+func (h *station) validateDevices(devices []Device) error {
+	for _, d := range devices {
+		if d.HasBit {
+			return fmt.Errorf("bit of word device %v is not supported", d)
+		}
+		if err := h.validateDevice(d.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// build4EFrame converts 3E frame request to 4E frame request.
+// 4E frame is 3E frame that sub header is replaced to 5400, serial number[2byte] and fixed value 0000[2byte].
+// serialNum is returned as it is in the response, so response can be matched to request.
+func (h *station) build4EFrame(request string, serialNum uint16) string {
+	serial := h.code.uintField(int64(serialNum), 2) // 2byte固定
+
+	return SUB_HEADER_4E +
+		serial +
+		"0000" +
+		request[len(SUB_HEADER):]
 }
 
 func (h *station) BuildAccessPath() {
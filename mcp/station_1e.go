@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// 1E frame is A compatible frame. FX3U-ENET and QnA compatible modules use 1E frame.
+// In 1E frame, sub header is command.
+const (
+	BIT_READ_COMMAND_1E  = "00"
+	READ_COMMAND_1E      = "01"
+	BIT_WRITE_COMMAND_1E = "02"
+	WRITE_COMMAND_1E     = "03"
+	HEALTH_CHECK_1E      = "16"
+
+	MONITORING_TIMER_1E = "0C00" // 250[msec] * 12 = 3[sec]
+)
+
+// maximum number of points of each command of 1E frame. plc returns completion code 57 if it is exceeded.
+const (
+	BIT_READ_MAX_POINTS_1E  = 256
+	READ_MAX_POINTS_1E      = 256
+	BIT_WRITE_MAX_POINTS_1E = 160
+	WRITE_MAX_POINTS_1E     = 256
+)
+
+// deviceCodes1E is device name and hex value map of 1E frame.
+// 1E frame device code is 2byte. value is little endian layout.
+var deviceCodes1E = map[string]string{
+	"X":  "2058",
+	"Y":  "2059",
+	"M":  "204D",
+	"L":  "204C",
+	"F":  "2046",
+	"S":  "2053",
+	"B":  "2042",
+	"D":  "2044",
+	"W":  "2057",
+	"R":  "2052",
+	"TS": "5354",
+	"TC": "4354",
+	"TN": "4E54",
+	"CS": "5343",
+	"CC": "4343",
+	"CN": "4E43",
+}
+
+// station1E is PLC that is accessed by 1E frame.
+// 1E frame can not specify network number, so only PC number is used.
+type station1E struct {
+	// PC Number
+	pcNum string
+}
+
+func NewStation1E(pcNum string) *station1E {
+	return &station1E{
+		pcNum: pcNum,
+	}
+}
+
+// local stn stn of 1E frame. local stn is 自局.
+func NewLocalStation1E() *station1E {
+	return &station1E{
+		pcNum: "FF", // 自局の場合はFF固定
+	}
+}
+
+func (h *station1E) BuildHealthCheckRequest() string {
+	returnDataNum := "05"      // 5 device. 1byte
+	returnData := "4142434445" // value is "ABCDE".
+
+	return HEALTH_CHECK_1E +
+		h.pcNum +
+		MONITORING_TIMER_1E +
+		returnDataNum +
+		returnData
+}
+
+// BuildReadRequest represents MCP read as word command of 1E frame.
+// deviceName is device code name like 'D' register.
+// offset is device offset addr.
+// numPoints is number of read device points. 256 points is expressed as 0.
+func (h *station1E) BuildReadRequest(deviceName string, offset, numPoints int64) string {
+	return READ_COMMAND_1E +
+		h.pcNum +
+		MONITORING_TIMER_1E +
+		h.deviceRange(deviceName, offset, numPoints)
+}
+
+// BuildBitReadRequest represents MCP read as bit command of 1E frame.
+// deviceName is device code name like 'M' register.
+// offset is device offset addr.
+// numPoints is number of read device points. 256 points is expressed as 0.
+func (h *station1E) BuildBitReadRequest(deviceName string, offset, numPoints int64) string {
+	return BIT_READ_COMMAND_1E +
+		h.pcNum +
+		MONITORING_TIMER_1E +
+		h.deviceRange(deviceName, offset, numPoints)
+}
+
+// BuildWriteRequest represents MCP write as word command of 1E frame.
+// deviceName is device code name like 'D' register.
+// offset is device offset addr.
+// numPoints is number of write device points. 256 points is expressed as 0.
+// writeData is the data to be written. If writeData is larger than 2*numPoints bytes,
+// data larger than 2*numPoints bytes is ignored.
+func (h *station1E) BuildWriteRequest(deviceName string, offset, numPoints int64, writeData []byte) string {
+	// 2 byte per 1 device point
+	writeHex := fmt.Sprintf("%X", writeData[0:2*numPoints])
+
+	return WRITE_COMMAND_1E +
+		h.pcNum +
+		MONITORING_TIMER_1E +
+		h.deviceRange(deviceName, offset, numPoints) +
+		writeHex
+}
+
+// BuildBitWriteRequest represents MCP write as bit command of 1E frame.
+// deviceName is device code name like 'M' register.
+// offset is device offset addr.
+// values are written from offset. number of write device points is len(values). 256 points is expressed as 0.
+func (h *station1E) BuildBitWriteRequest(deviceName string, offset int64, values []bool) string {
+	return BIT_WRITE_COMMAND_1E +
+		h.pcNum +
+		MONITORING_TIMER_1E +
+		h.deviceRange(deviceName, offset, int64(len(values))) +
+		Binary.bitsField(values)
+}
+
+// deviceRange returns head device number[4byte], device code[2byte], number of points[1byte] and fixed value 00.
+func (h *station1E) deviceRange(deviceName string, offset, numPoints int64) string {
+	// get device symbol hex layout
+	deviceCode := deviceCodes1E[deviceName]
+
+	// offset convert to little endian layout. 1E frame is 4byte.
+	offsetBuff := new(bytes.Buffer)
+	_ = binary.Write(offsetBuff, binary.LittleEndian, offset)
+	offsetHex := fmt.Sprintf("%X", offsetBuff.Bytes()[0:4])
+
+	// 256 points is 0 because of 1byte
+	points := fmt.Sprintf("%02X", numPoints%256)
+
+	return offsetHex +
+		deviceCode +
+		points +
+		"00" // 固定値
+}
@@ -0,0 +1,54 @@
+package mcp
+
+import "testing"
+
+func TestStation1E_BuildReadRequest(t *testing.T) {
+	station := NewLocalStation1E()
+	request := station.BuildReadRequest("D", 100, 3)
+
+	if request != "01FF0C00640000002044"+"0300" {
+		t.Fatalf("expected %v but actual is %v", "01FF0C0064000000204403"+"00", request)
+	}
+
+	// 256 points is expressed as 0
+	request2 := station.BuildReadRequest("D", 100, 256)
+	if request2 != "01FF0C00640000002044"+"0000" {
+		t.Fatalf("expected %v but actual is %v", "01FF0C0064000000204400"+"00", request2)
+	}
+}
+
+func TestStation1E_BuildBitReadRequest(t *testing.T) {
+	station := NewLocalStation1E()
+	request := station.BuildBitReadRequest("M", 100, 12)
+
+	if request != "00FF0C0064000000204D0C00" {
+		t.Fatalf("expected %v but actual is %v", "00FF0C0064000000204D0C00", request)
+	}
+}
+
+func TestStation1E_BuildWriteRequest(t *testing.T) {
+	station := NewLocalStation1E()
+	request := station.BuildWriteRequest("D", 100, 2, []byte{0x34, 0x12, 0x02, 0x00, 0xFF})
+
+	if request != "03FF0C0064000000204402003412"+"0200" {
+		t.Fatalf("expected %v but actual is %v", "03FF0C00640000002044020034120200", request)
+	}
+}
+
+func TestStation1E_BuildHealthCheckRequest(t *testing.T) {
+	station := NewLocalStation1E()
+	request := station.BuildHealthCheckRequest()
+
+	if request != "16FF0C00054142434445" {
+		t.Fatalf("expected %v but actual is %v", "16FF0C00054142434445", request)
+	}
+}
+
+func TestStation1E_BuildBitWriteRequest(t *testing.T) {
+	station := NewLocalStation1E()
+	request := station.BuildBitWriteRequest("M", 10, []bool{true, false, true})
+
+	if request != "02FF0C000A000000204D0300"+"1010" {
+		t.Fatalf("expected %v but actual is %v", "02FF0C000A000000204D03001010", request)
+	}
+}
@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuildBlockReadRequest represents MCP multiple block batch read command.
+// wordBlocks are word device blocks like D100-D120, and bitBlocks are bit device blocks read as word units like M0-M63.
+func (h *station) BuildBlockReadRequest(wordBlocks, bitBlocks []Block) (string, error) {
+	if err := h.validateBlockDevices(wordBlocks, bitBlocks); err != nil {
+		return "", err
+	}
+
+	// word device blocks[1byte] and bit device blocks[1byte]
+	requestStr := h.code.layout(BLOCK_READ_COMMAND) +
+		h.code.layout(h.addressing.subCommand(BLOCK_SUB_COMMAND)) +
+		h.code.uintField(int64(len(wordBlocks)), 1) +
+		h.code.uintField(int64(len(bitBlocks)), 1)
+
+	// each block is device and number of points[2byte]
+	for _, b := range append(append([]Block{}, wordBlocks...), bitBlocks...) {
+		requestStr += h.deviceField(b.Name, b.Offset) + h.code.uintField(b.NumPoints, 2)
+	}
+
+	return h.buildFrame(requestStr), nil
+}
+
+// BuildBlockWriteRequest represents MCP multiple block batch write command.
+// wordBlocks are word device blocks written with wordData, and bitBlocks are bit device blocks written as word units with bitData.
+// Each block and data must be same order, and length of data must be same as number of points of the block.
+func (h *station) BuildBlockWriteRequest(wordBlocks []Block, wordData [][]uint16, bitBlocks []Block, bitData [][]uint16) (string, error) {
+	if err := h.validateBlockDevices(wordBlocks, bitBlocks); err != nil {
+		return "", err
+	}
+
+	// word device blocks[1byte] and bit device blocks[1byte]
+	requestStr := h.code.layout(BLOCK_WRITE_COMMAND) +
+		h.code.layout(h.addressing.subCommand(BLOCK_SUB_COMMAND)) +
+		h.code.uintField(int64(len(wordBlocks)), 1) +
+		h.code.uintField(int64(len(bitBlocks)), 1)
+
+	// each block is device, number of points[2byte] and write data
+	blocks := append(append([]Block{}, wordBlocks...), bitBlocks...)
+	data := append(append([][]uint16{}, wordData...), bitData...)
+	for i, b := range blocks {
+		requestStr += h.deviceField(b.Name, b.Offset) + h.code.uintField(b.NumPoints, 2)
+		for _, v := range data[i][0:b.NumPoints] {
+			requestStr += h.code.uintField(int64(v), 2)
+		}
+	}
+
+	return h.buildFrame(requestStr), nil
+}
+
+// validateBlockDevices checks that devices of wordBlocks are word device and devices of bitBlocks are bit device.
+func (h *station) validateBlockDevices(wordBlocks, bitBlocks []Block) error {
+	for _, b := range wordBlocks {
+		if err := h.validateBlockDevice(b, WordDevice); err != nil {
+			return err
+		}
+	}
+	for _, b := range bitBlocks {
+		if err := h.validateBlockDevice(b, BitDevice); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBlockDevice checks that device of block is deviceType. bit of word device like D100.F can not be block.
+func (h *station) validateBlockDevice(b Block, deviceType DeviceType) error {
+	if strings.Contains(b.Name, ".") {
+		return fmt.Errorf("bit of word device %v can not be block", b.Name)
+	}
+	if err := h.validateDevice(b.Name); err != nil {
+		return err
+	}
+	if deviceCatalogue[b.Name].Type != deviceType {
+		if deviceType == WordDevice {
+			return fmt.Errorf("device %v of word block must be word device", b.Name)
+		}
+		return fmt.Errorf("device %v of bit block must be bit device", b.Name)
+	}
+	return nil
+}
+
+// validateBlockReadPoints checks number of blocks and points of multiple block batch read.
+// number of blocks must be less than or equal to 120 (MELSEC iQ-R: 60), and total points must be less than or equal to 960.
+func (h *station) validateBlockReadPoints(blocks []Block) error {
+	limit := 120
+	if h.addressing == IQRAddressing {
+		limit = 60
+	}
+	if len(blocks) > limit {
+		return fmt.Errorf("number of blocks must be less than or equal to %v but actual is %v", limit, len(blocks))
+	}
+
+	var points int64
+	for _, b := range blocks {
+		points += b.NumPoints
+	}
+	if points > 960 {
+		return fmt.Errorf("total points of blocks must be less than or equal to %v but actual is %v", 960, points)
+	}
+	return nil
+}
+
+// validateBlockWritePoints checks number of blocks and points of multiple block batch write.
+// number of blocks*4 (MELSEC iQ-R: *9) + total points must be less than or equal to 960.
+func (h *station) validateBlockWritePoints(blocks []Block) error {
+	weight := int64(4)
+	if h.addressing == IQRAddressing {
+		weight = 9
+	}
+
+	points := weight * int64(len(blocks))
+	for _, b := range blocks {
+		points += b.NumPoints
+	}
+	if points > 960 {
+		return fmt.Errorf("blocks*%v + total points must be less than or equal to %v but actual is %v", weight, 960, points)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import "testing"
+
+func TestStation_BuildBlockReadRequest(t *testing.T) {
+	station := NewLocalStation()
+	wordBlocks := []Block{{Name: "D", Offset: 100, NumPoints: 2}, {Name: "W", Offset: 0x1F, NumPoints: 1}}
+	bitBlocks := []Block{{Name: "M", Offset: 0, NumPoints: 4}}
+
+	request := mustRequest(t)(station.BuildBlockReadRequest(wordBlocks, bitBlocks))
+	expected := "500000FFFF0300" + "1A00" + "1000" + "0604" + "0000" + "0201" + "640000A8" + "0200" + "1F0000B4" + "0100" + "00000090" + "0400"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+
+	station.code = Ascii
+	request2 := mustRequest(t)(station.BuildBlockReadRequest(wordBlocks, bitBlocks))
+	expected2 := "500000FF03FF00" + "0034" + "0010" + "0406" + "0000" + "0201" + "D*000100" + "0002" + "W*00001F" + "0001" + "M*000000" + "0004"
+	if request2 != expected2 {
+		t.Fatalf("expected %v but actual is %v", expected2, request2)
+	}
+}
+
+func TestStation_BuildBlockWriteRequest(t *testing.T) {
+	station := NewLocalStation()
+	wordBlocks := []Block{{Name: "D", Offset: 100, NumPoints: 2}}
+	bitBlocks := []Block{{Name: "M", Offset: 0, NumPoints: 1}}
+
+	request := mustRequest(t)(station.BuildBlockWriteRequest(wordBlocks, [][]uint16{{0x1234, 0x5678}}, bitBlocks, [][]uint16{{0x0005}}))
+	expected := "500000FFFF0300" + "1A00" + "1000" + "0614" + "0000" + "0101" + "640000A8" + "0200" + "34127856" + "00000090" + "0100" + "0500"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+}
+
+func TestStation_ValidateBlockPoints(t *testing.T) {
+	station := NewLocalStation()
+	blocks := []Block{{Name: "D", Offset: 0, NumPoints: 960}}
+	if err := station.validateBlockReadPoints(blocks); err != nil {
+		t.Errorf("unexpected validate err: %v", err)
+	}
+	if err := station.validateBlockWritePoints(blocks); err == nil {
+		t.Errorf("expected error of limit over but actual is nil")
+	}
+
+	if err := station.validateBlockReadPoints(make([]Block, 121)); err == nil {
+		t.Errorf("expected error of limit over but actual is nil")
+	}
+}
+
+func TestStation_ValidateBlockDevices(t *testing.T) {
+	station := NewLocalStation()
+	word := []Block{{Name: "D", Offset: 0, NumPoints: 1}}
+	bit := []Block{{Name: "M", Offset: 0, NumPoints: 1}}
+
+	if err := station.validateBlockDevices(word, bit); err != nil {
+		t.Errorf("unexpected validate err: %v", err)
+	}
+	if _, err := station.BuildBlockReadRequest(bit, nil); err == nil {
+		t.Errorf("expected error of bit device in word blocks but actual is nil")
+	}
+	if _, err := station.BuildBlockWriteRequest(nil, nil, word, [][]uint16{{0x0001}}); err == nil {
+		t.Errorf("expected error of word device in bit blocks but actual is nil")
+	}
+	if err := station.validateBlockDevices([]Block{{Name: "D100.F", Offset: 0, NumPoints: 1}}, nil); err == nil {
+		t.Errorf("expected error of bit of word device but actual is nil")
+	}
+}
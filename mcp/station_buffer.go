@@ -0,0 +1,51 @@
+package mcp
+
+import "fmt"
+
+// BUFFER_MAX_WORDS is maximum number of words of intelligent function module buffer memory read and write. 1920[byte]
+const BUFFER_MAX_WORDS = 960
+
+// BuildBufferReadRequest represents MCP intelligent function module buffer memory read command.
+// startIO is start I/O number of the module like 0x0030. address is buffer memory address in word units.
+// numWords is number of read words.
+func (h *station) BuildBufferReadRequest(startIO uint16, address, numWords int64) string {
+	requestStr := h.code.layout(MODULE_BUFFER_READ_COMMAND) +
+		h.code.layout(MODULE_BUFFER_SUB_COMMAND) +
+		h.bufferRange(startIO, address, numWords)
+
+	return h.buildFrame(requestStr)
+}
+
+// BuildBufferWriteRequest represents MCP intelligent function module buffer memory write command.
+// startIO is start I/O number of the module like 0x0030. address is buffer memory address in word units.
+// values are written from address.
+func (h *station) BuildBufferWriteRequest(startIO uint16, address int64, values []uint16) string {
+	requestStr := h.code.layout(MODULE_BUFFER_WRITE_COMMAND) +
+		h.code.layout(MODULE_BUFFER_SUB_COMMAND) +
+		h.bufferRange(startIO, address, int64(len(values)))
+
+	for _, v := range values {
+		requestStr += h.code.uintField(int64(v), 2)
+	}
+
+	return h.buildFrame(requestStr)
+}
+
+// bufferRange returns start address[4byte], number of bytes[2byte] and module number[2byte].
+// address and number of bytes are byte units. module number is upper 3 digits of start I/O number like 0003.
+func (h *station) bufferRange(startIO uint16, address, numWords int64) string {
+	return h.code.uintField(2*address, 4) +
+		h.code.uintField(2*numWords, 2) +
+		h.code.uintField(int64(startIO>>4), 2)
+}
+
+// validateBufferRange checks buffer memory range of one request.
+func validateBufferRange(address, numWords int64) error {
+	if address < 0 {
+		return fmt.Errorf("buffer memory address must not be negative but actual is %v", address)
+	}
+	if numWords < 1 || numWords > BUFFER_MAX_WORDS {
+		return fmt.Errorf("number of words must be 1 to %v but actual is %v", BUFFER_MAX_WORDS, numWords)
+	}
+	return nil
+}
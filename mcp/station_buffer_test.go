@@ -0,0 +1,30 @@
+package mcp
+
+import "testing"
+
+func TestStation_BuildBufferReadRequest(t *testing.T) {
+	station := NewLocalStation()
+
+	request := station.BuildBufferReadRequest(0x0030, 100, 10)
+	expected := "500000FFFF0300" + "0E00" + "1000" + "1306" + "0000" + "C8000000" + "1400" + "0300"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+
+	station.code = Ascii
+	request2 := station.BuildBufferReadRequest(0x0030, 100, 10)
+	expected2 := "500000FF03FF00" + "001C" + "0010" + "0613" + "0000" + "000000C8" + "0014" + "0003"
+	if request2 != expected2 {
+		t.Fatalf("expected %v but actual is %v", expected2, request2)
+	}
+}
+
+func TestStation_BuildBufferWriteRequest(t *testing.T) {
+	station := NewLocalStation()
+
+	request := station.BuildBufferWriteRequest(0x0030, 100, []uint16{0x1234, 0x0001})
+	expected := "500000FFFF0300" + "1200" + "1000" + "1316" + "0000" + "C8000000" + "0400" + "0300" + "3412" + "0100"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+}
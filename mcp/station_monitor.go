@@ -0,0 +1,26 @@
+package mcp
+
+// BuildEntryMonitorRequest represents MCP monitor registration command.
+// words are devices monitored as word, and dwords are devices monitored as double word.
+// Total number of words and dwords must be less than or equal to RandomReadLimit.
+// Registration is kept by the ethernet module while the connection is alive.
+func (h *station) BuildEntryMonitorRequest(words, dwords []Device) (string, error) {
+	if err := h.validateDevices(append(append([]Device{}, words...), dwords...)); err != nil {
+		return "", err
+	}
+
+	requestStr := h.code.layout(ENTRY_MONITOR_COMMAND) +
+		h.code.layout(h.addressing.subCommand(ENTRY_MONITOR_SUB_COMMAND)) +
+		h.randomDevicesField(words, dwords)
+
+	return h.buildFrame(requestStr), nil
+}
+
+// BuildMonitorRequest represents MCP monitor command.
+// values of registered devices are returned in the same layout as random read.
+func (h *station) BuildMonitorRequest() string {
+	requestStr := h.code.layout(MONITOR_COMMAND) +
+		h.code.layout(MONITOR_SUB_COMMAND)
+
+	return h.buildFrame(requestStr)
+}
@@ -0,0 +1,32 @@
+package mcp
+
+import "testing"
+
+func TestStation_BuildEntryMonitorRequest(t *testing.T) {
+	station := NewLocalStation()
+	words := []Device{{Name: "D", Offset: 100}}
+	dwords := []Device{{Name: "D", Offset: 200}}
+
+	request := mustRequest(t)(station.BuildEntryMonitorRequest(words, dwords))
+	expected := "500000FFFF0300" + "1000" + "1000" + "0108" + "0000" + "0101" + "640000A8" + "C80000A8"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+}
+
+func TestStation_BuildMonitorRequest(t *testing.T) {
+	station := NewLocalStation()
+
+	request := station.BuildMonitorRequest()
+	expected := "500000FFFF0300" + "0600" + "1000" + "0208" + "0000"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+
+	station.code = Ascii
+	request2 := station.BuildMonitorRequest()
+	expected2 := "500000FF03FF00" + "000C" + "0010" + "0802" + "0000"
+	if request2 != expected2 {
+		t.Fatalf("expected %v but actual is %v", expected2, request2)
+	}
+}
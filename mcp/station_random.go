@@ -0,0 +1,118 @@
+package mcp
+
+import "fmt"
+
+// BuildRandomReadRequest represents MCP random read command.
+// words are devices read as word, and dwords are devices read as double word.
+// Total number of words and dwords must be less than or equal to RandomReadLimit.
+func (h *station) BuildRandomReadRequest(words, dwords []Device) (string, error) {
+	if err := h.validateDevices(append(append([]Device{}, words...), dwords...)); err != nil {
+		return "", err
+	}
+	requestStr := h.code.layout(RANDOM_READ_COMMAND) +
+		h.code.layout(h.addressing.subCommand(RANDOM_READ_SUB_COMMAND)) +
+		h.randomDevicesField(words, dwords)
+
+	return h.buildFrame(requestStr), nil
+}
+
+// randomDevicesField returns word access points[1byte], double word access points[1byte] and each device.
+func (h *station) randomDevicesField(words, dwords []Device) string {
+	s := h.code.uintField(int64(len(words)), 1) +
+		h.code.uintField(int64(len(dwords)), 1)
+
+	for _, d := range words {
+		s += h.deviceField(d.Name, d.Offset)
+	}
+	for _, d := range dwords {
+		s += h.deviceField(d.Name, d.Offset)
+	}
+	return s
+}
+
+// RandomReadLimit returns max number of word and double word access points of random read.
+func (h *station) RandomReadLimit() int {
+	if h.addressing == IQRAddressing {
+		return 96
+	}
+	return 192
+}
+
+// BuildRandomWriteRequest represents MCP random write as word command.
+// words are devices written as word with wordValues, and dwords are devices written as double word with dwordValues.
+// Each device and value must be same order and same length.
+func (h *station) BuildRandomWriteRequest(words []Device, wordValues []uint16, dwords []Device, dwordValues []uint32) (string, error) {
+	if err := h.validateDevices(append(append([]Device{}, words...), dwords...)); err != nil {
+		return "", err
+	}
+
+	// word access points[1byte] and double word access points[1byte]
+	requestStr := h.code.layout(RANDOM_WRITE_COMMAND) +
+		h.code.layout(h.addressing.subCommand(RANDOM_WRITE_SUB_COMMAND)) +
+		h.code.uintField(int64(len(words)), 1) +
+		h.code.uintField(int64(len(dwords)), 1)
+
+	// each device is followed by write data
+	for i, d := range words {
+		requestStr += h.deviceField(d.Name, d.Offset) + h.code.uintField(int64(wordValues[i]), 2)
+	}
+	for i, d := range dwords {
+		requestStr += h.deviceField(d.Name, d.Offset) + h.code.uintField(int64(dwordValues[i]), 4)
+	}
+
+	return h.buildFrame(requestStr), nil
+}
+
+// BuildRandomBitWriteRequest represents MCP random write as bit command.
+// bits are devices written as bit with values. Each device and value must be same order and same length.
+func (h *station) BuildRandomBitWriteRequest(bits []Device, values []bool) (string, error) {
+	if err := h.validateDevices(bits); err != nil {
+		return "", err
+	}
+
+	// bit access points[1byte]
+	requestStr := h.code.layout(RANDOM_WRITE_COMMAND) +
+		h.code.layout(h.addressing.subCommand(RANDOM_BIT_WRITE_SUB_COMMAND)) +
+		h.code.uintField(int64(len(bits)), 1)
+
+	// set/reset is 1byte. MELSEC iQ-R is 2byte
+	setResetSize := 1
+	if h.addressing == IQRAddressing {
+		setResetSize = 2
+	}
+	for i, d := range bits {
+		var v int64
+		if values[i] {
+			v = 1
+		}
+		requestStr += h.deviceField(d.Name, d.Offset) + h.code.uintField(v, setResetSize)
+	}
+
+	return h.buildFrame(requestStr), nil
+}
+
+// validateRandomWritePoints checks number of points of random write as word command.
+// word access points*12 + double word access points*14 must be less than or equal to 1920 (MELSEC iQ-R: 960).
+func (h *station) validateRandomWritePoints(numWords, numDWords int) error {
+	limit := 1920
+	if h.addressing == IQRAddressing {
+		limit = 960
+	}
+	if points := numWords*12 + numDWords*14; points > limit {
+		return fmt.Errorf("random write points (word*12 + double word*14) must be less than or equal to %v but actual is %v", limit, points)
+	}
+	return nil
+}
+
+// validateRandomBitWritePoints checks number of points of random write as bit command.
+// bit access points must be less than or equal to 188 (MELSEC iQ-R: 94).
+func (h *station) validateRandomBitWritePoints(numBits int) error {
+	limit := 188
+	if h.addressing == IQRAddressing {
+		limit = 94
+	}
+	if numBits > limit {
+		return fmt.Errorf("random bit write points must be less than or equal to %v but actual is %v", limit, numBits)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package mcp
+
+import "testing"
+
+func TestStation_BuildRandomReadRequest(t *testing.T) {
+	station := NewLocalStation()
+	words := []Device{{Name: "D", Offset: 100}, {Name: "W", Offset: 0x1A}}
+	dwords := []Device{{Name: "D", Offset: 200}}
+
+	request := mustRequest(t)(station.BuildRandomReadRequest(words, dwords))
+	expected := "500000FFFF0300" + "1400" + "1000" + "0304" + "0000" + "0201" + "640000A8" + "1A0000B4" + "C80000A8"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+
+	station.code = Ascii
+	request2 := mustRequest(t)(station.BuildRandomReadRequest(words, dwords))
+	expected2 := "500000FF03FF00" + "0028" + "0010" + "0403" + "0000" + "0201" + "D*000100" + "W*00001A" + "D*000200"
+	if request2 != expected2 {
+		t.Fatalf("expected %v but actual is %v", expected2, request2)
+	}
+}
+
+func TestStation_BuildRandomWriteRequest(t *testing.T) {
+	station := NewLocalStation()
+
+	request := mustRequest(t)(station.BuildRandomWriteRequest([]Device{{Name: "D", Offset: 100}}, []uint16{0x1234}, []Device{{Name: "D", Offset: 200}}, []uint32{0x12345678}))
+	expected := "500000FFFF0300" + "1600" + "1000" + "0214" + "0000" + "0101" + "640000A8" + "3412" + "C80000A8" + "78563412"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+}
+
+func TestStation_BuildRandomBitWriteRequest(t *testing.T) {
+	bits := []Device{{Name: "M", Offset: 10}, {Name: "Y", Offset: 0x2F}}
+	values := []bool{true, false}
+
+	cases := []struct {
+		name       string
+		code       Code
+		addressing Addressing
+		expected   string
+	}{
+		{
+			name:     "binary",
+			code:     Binary,
+			expected: "500000FFFF0300" + "1100" + "1000" + "0214" + "0100" + "02" + "0A000090" + "01" + "2F00009D" + "00",
+		},
+		{
+			name:       "binary iQ-R",
+			code:       Binary,
+			addressing: IQRAddressing,
+			expected:   "500000FFFF0300" + "1700" + "1000" + "0214" + "0300" + "02" + "0A0000009000" + "0100" + "2F0000009D00" + "0000",
+		},
+		{
+			name:     "ascii",
+			code:     Ascii,
+			expected: "500000FF03FF00" + "0022" + "0010" + "1402" + "0001" + "02" + "M*000010" + "01" + "Y*00002F" + "00",
+		},
+	}
+
+	for _, v := range cases {
+		station := NewLocalStation()
+		station.code = v.code
+		station.addressing = v.addressing
+
+		if actual := mustRequest(t)(station.BuildRandomBitWriteRequest(bits, values)); actual != v.expected {
+			t.Errorf("%v: expected %v but actual is %v", v.name, v.expected, actual)
+		}
+	}
+}
+
+func TestStation_ValidateRandomWritePoints(t *testing.T) {
+	station := NewLocalStation()
+	if err := station.validateRandomWritePoints(160, 0); err != nil {
+		t.Errorf("unexpected validate err: %v", err)
+	}
+	if err := station.validateRandomWritePoints(160, 1); err == nil {
+		t.Errorf("expected error of limit over but actual is nil")
+	}
+	if err := station.validateRandomBitWritePoints(189); err == nil {
+		t.Errorf("expected error of limit over but actual is nil")
+	}
+
+	station.addressing = IQRAddressing
+	if err := station.validateRandomWritePoints(80, 1); err == nil {
+		t.Errorf("expected error of limit over but actual is nil")
+	}
+}
@@ -0,0 +1,75 @@
+package mcp
+
+// ClearMode is device memory clear mode of remote RUN.
+type ClearMode int
+
+const (
+	// NoClear does not clear device memory.
+	NoClear ClearMode = iota
+	// ClearExceptLatch clears device memory except latch range.
+	ClearExceptLatch
+	// ClearAll clears all device memory including latch range.
+	ClearAll
+)
+
+// RemoteRunOption is option of remote RUN.
+type RemoteRunOption struct {
+	// Force executes remote RUN even if other device executes remote STOP or PAUSE.
+	Force bool
+	// Clear is device memory clear mode.
+	Clear ClearMode
+}
+
+// BuildRemoteRunRequest represents MCP remote RUN command.
+func (h *station) BuildRemoteRunRequest(opt RemoteRunOption) string {
+
+	// mode[2byte] 0001: 強制実行しない, 0003: 強制実行する
+	mode := int64(0x0001)
+	if opt.Force {
+		mode = 0x0003
+	}
+
+	requestStr := h.code.layout(REMOTE_RUN_COMMAND) +
+		h.code.layout(REMOTE_SUB_COMMAND) +
+		h.code.uintField(mode, 2) +
+		h.code.uintField(int64(opt.Clear), 1) + // clear mode[1byte]
+		h.code.uintField(0, 1) // 固定値
+
+	return h.buildFrame(requestStr)
+}
+
+// BuildRemoteStopRequest represents MCP remote STOP command.
+func (h *station) BuildRemoteStopRequest() string {
+	return h.buildRemoteRequest(REMOTE_STOP_COMMAND, 0x0001) // 固定値
+}
+
+// BuildRemotePauseRequest represents MCP remote PAUSE command.
+// If force is true, remote PAUSE is executed even if other device executes remote STOP.
+func (h *station) BuildRemotePauseRequest(force bool) string {
+
+	// mode[2byte] 0001: 強制実行しない, 0003: 強制実行する
+	mode := int64(0x0001)
+	if force {
+		mode = 0x0003
+	}
+	return h.buildRemoteRequest(REMOTE_PAUSE_COMMAND, mode)
+}
+
+// BuildRemoteLatchClearRequest represents MCP remote latch clear command. plc cpu must be STOP.
+func (h *station) BuildRemoteLatchClearRequest() string {
+	return h.buildRemoteRequest(REMOTE_LATCH_CLEAR_COMMAND, 0x0001) // 固定値
+}
+
+// BuildRemoteResetRequest represents MCP remote RESET command. plc cpu must be STOP.
+func (h *station) BuildRemoteResetRequest() string {
+	return h.buildRemoteRequest(REMOTE_RESET_COMMAND, 0x0001) // 固定値
+}
+
+// buildRemoteRequest builds remote operation request that has 2byte data after sub command.
+func (h *station) buildRemoteRequest(command string, data int64) string {
+	requestStr := h.code.layout(command) +
+		h.code.layout(REMOTE_SUB_COMMAND) +
+		h.code.uintField(data, 2)
+
+	return h.buildFrame(requestStr)
+}
@@ -0,0 +1,47 @@
+package mcp
+
+import "testing"
+
+func TestStation_BuildRemoteRunRequest(t *testing.T) {
+	station := NewLocalStation()
+
+	request := station.BuildRemoteRunRequest(RemoteRunOption{})
+	expected := "500000FFFF0300" + "0A00" + "1000" + "0110" + "0000" + "0100" + "00" + "00"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+
+	request2 := station.BuildRemoteRunRequest(RemoteRunOption{Force: true, Clear: ClearAll})
+	expected2 := "500000FFFF0300" + "0A00" + "1000" + "0110" + "0000" + "0300" + "02" + "00"
+	if request2 != expected2 {
+		t.Fatalf("expected %v but actual is %v", expected2, request2)
+	}
+
+	station.code = Ascii
+	request3 := station.BuildRemoteRunRequest(RemoteRunOption{Clear: ClearExceptLatch})
+	expected3 := "500000FF03FF00" + "0014" + "0010" + "1001" + "0000" + "0001" + "01" + "00"
+	if request3 != expected3 {
+		t.Fatalf("expected %v but actual is %v", expected3, request3)
+	}
+}
+
+func TestStation_BuildRemoteRequest(t *testing.T) {
+	station := NewLocalStation()
+
+	cases := []struct {
+		name     string
+		request  string
+		expected string
+	}{
+		{"STOP", station.BuildRemoteStopRequest(), "500000FFFF0300" + "0800" + "1000" + "0210" + "0000" + "0100"},
+		{"PAUSE", station.BuildRemotePauseRequest(false), "500000FFFF0300" + "0800" + "1000" + "0310" + "0000" + "0100"},
+		{"PAUSE force", station.BuildRemotePauseRequest(true), "500000FFFF0300" + "0800" + "1000" + "0310" + "0000" + "0300"},
+		{"latch clear", station.BuildRemoteLatchClearRequest(), "500000FFFF0300" + "0800" + "1000" + "0510" + "0000" + "0100"},
+		{"RESET", station.BuildRemoteResetRequest(), "500000FFFF0300" + "0800" + "1000" + "0610" + "0000" + "0100"},
+	}
+	for _, c := range cases {
+		if c.request != c.expected {
+			t.Fatalf("%v: expected %v but actual is %v", c.name, c.expected, c.request)
+		}
+	}
+}
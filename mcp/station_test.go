@@ -4,14 +4,198 @@ import "testing"
 
 func TestStation_BuildRRequest(t *testing.T) {
 	station := NewLocalStation()
-	request := station.BuildReadRequest("D", 300, 3)
+	request := mustRequest(t)(station.BuildReadRequest("D", 300, 3))
 
 	if request != "500000FFFF03000C001000010400002C0100A80300" {
 		t.Fatalf("expected %v but actual is %v", "500000FFFF03000C001000010400002C0100A80300", request)
 	}
 
-	request2 := station.BuildReadRequest("D", 500, 50)
+	request2 := mustRequest(t)(station.BuildReadRequest("D", 500, 50))
 	if request2 != "500000FFFF03000C00100001040000F40100A83200" {
 		t.Fatalf("expected %v but actual is %v", "500000FFFF03000C00100001040000F40100A83200", request2)
 	}
 }
+
+func TestStation_Build4EFrame(t *testing.T) {
+	station := NewLocalStation()
+	request := station.build4EFrame(mustRequest(t)(station.BuildReadRequest("D", 300, 3)), 0x1234)
+
+	if request != "540034120000"+"00FFFF03000C001000010400002C0100A80300" {
+		t.Fatalf("expected %v but actual is %v", "54003412000000FFFF03000C001000010400002C0100A80300", request)
+	}
+}
+
+func TestStation_BuildAsciiRequest(t *testing.T) {
+	station := NewLocalStation()
+	station.code = Ascii
+
+	cases := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{
+			name:     "read",
+			actual:   mustRequest(t)(station.BuildReadRequest("D", 100, 3)),
+			expected: "500000FF03FF00" + "0018" + "0010" + "0401" + "0000" + "D*000100" + "0003",
+		},
+		{
+			name:     "bit read with hex device number",
+			actual:   mustRequest(t)(station.BuildBitReadRequest("X", 0x1A0, 5)),
+			expected: "500000FF03FF00" + "0018" + "0010" + "0401" + "0001" + "X*0001A0" + "0005",
+		},
+		{
+			name:     "write",
+			actual:   mustRequest(t)(station.BuildWriteRequest("D", 100, 2, []byte{0x34, 0x12, 0x02, 0x00})),
+			expected: "500000FF03FF00" + "0020" + "0010" + "1401" + "0000" + "D*000100" + "0002" + "12340002",
+		},
+		{
+			name:     "health check",
+			actual:   station.BuildHealthCheckRequest(),
+			expected: "500000FF03FF00" + "0015" + "0010" + "0619" + "0000" + "0005" + "ABCDE",
+		},
+		{
+			name:     "4E frame",
+			actual:   station.build4EFrame(mustRequest(t)(station.BuildReadRequest("D", 100, 3)), 0x1234),
+			expected: "5400" + "1234" + "0000" + "00FF03FF00" + "0018" + "0010" + "0401" + "0000" + "D*000100" + "0003",
+		},
+	}
+
+	for _, v := range cases {
+		if v.actual != v.expected {
+			t.Errorf("%v: expected %v but actual is %v", v.name, v.expected, v.actual)
+		}
+	}
+}
+
+func TestStation_BuildIQRRequest(t *testing.T) {
+	station := NewLocalStation()
+	station.addressing = IQRAddressing
+
+	asciiStation := NewLocalStation()
+	asciiStation.addressing = IQRAddressing
+	asciiStation.code = Ascii
+
+	cases := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{
+			name:     "read",
+			actual:   mustRequest(t)(station.BuildReadRequest("D", 100, 3)),
+			expected: "500000FFFF0300" + "0E00" + "1000" + "0104" + "0200" + "64000000A800" + "0300",
+		},
+		{
+			name:     "bit read",
+			actual:   mustRequest(t)(station.BuildBitReadRequest("M", 100, 3)),
+			expected: "500000FFFF0300" + "0E00" + "1000" + "0104" + "0300" + "640000009000" + "0300",
+		},
+		{
+			name:     "write long device",
+			actual:   mustRequest(t)(station.BuildWriteRequest("LZ", 1, 2, []byte{0x34, 0x12, 0x02, 0x00})),
+			expected: "500000FFFF0300" + "1200" + "1000" + "0114" + "0200" + "010000006200" + "0200" + "34120200",
+		},
+		{
+			name:     "large ZR",
+			actual:   mustRequest(t)(station.BuildReadRequest("ZR", 20000000, 1)),
+			expected: "500000FFFF0300" + "0E00" + "1000" + "0104" + "0200" + "002D3101B000" + "0100",
+		},
+		{
+			name:     "ascii read",
+			actual:   mustRequest(t)(asciiStation.BuildReadRequest("D", 100, 3)),
+			expected: "500000FF03FF00" + "001E" + "0010" + "0401" + "0002" + "D***0000000100" + "0003",
+		},
+		{
+			name:     "ascii long device",
+			actual:   mustRequest(t)(asciiStation.BuildReadRequest("LTN", 10, 4)),
+			expected: "500000FF03FF00" + "001E" + "0010" + "0401" + "0002" + "LTN*0000000010" + "0004",
+		},
+	}
+
+	for _, v := range cases {
+		if v.actual != v.expected {
+			t.Errorf("%v: expected %v but actual is %v", v.name, v.expected, v.actual)
+		}
+	}
+}
+
+func TestStation_ValidateDevice(t *testing.T) {
+	station := NewLocalStation()
+	if err := station.validateDevice("D"); err != nil {
+		t.Errorf("unexpected validate err: %v", err)
+	}
+	if err := station.validateDevice("LTN"); err == nil {
+		t.Errorf("expected error of iQ-R only device but actual is nil")
+	}
+	if err := station.validateDevice("QQ"); err == nil {
+		t.Errorf("expected error of unknown device but actual is nil")
+	}
+
+	station.addressing = IQRAddressing
+	if err := station.validateDevice("LTN"); err != nil {
+		t.Errorf("unexpected validate err: %v", err)
+	}
+
+	station.series = SeriesIQF
+	if err := station.validateDevice("ZR"); err == nil {
+		t.Errorf("expected error of device not supported by iQ-F but actual is nil")
+	}
+}
+
+func TestStation_BuildReadCPUModelRequest(t *testing.T) {
+	station := NewLocalStation()
+
+	request := station.BuildReadCPUModelRequest()
+	expected := "500000FFFF0300" + "0600" + "1000" + "0101" + "0000"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+}
+
+func TestStation_BuildBitWriteRequest(t *testing.T) {
+	station := NewLocalStation()
+	values := []bool{true, false, true}
+
+	request := mustRequest(t)(station.BuildBitWriteRequest("M", 10, values))
+	expected := "500000FFFF0300" + "0E00" + "1000" + "0114" + "0100" + "0A000090" + "0300" + "1010"
+	if request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+
+	station.code = Ascii
+	request2 := mustRequest(t)(station.BuildBitWriteRequest("M", 10, values))
+	expected2 := "500000FF03FF00" + "001B" + "0010" + "1401" + "0001" + "M*000010" + "0003" + "101"
+	if request2 != expected2 {
+		t.Fatalf("expected %v but actual is %v", expected2, request2)
+	}
+}
+
+// mustRequest returns function that returns request, or fails the test if request builder returns error.
+// It is curried because multiple return values of request builder can not be passed with t.
+func mustRequest(t *testing.T) func(request string, err error) string {
+	return func(request string, err error) string {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected request builder err: %v", err)
+		}
+		return request
+	}
+}
+
+func TestStation_BuildDeviceRequest(t *testing.T) {
+	station := NewLocalStation()
+
+	request := mustRequest(t)(station.BuildReadDeviceRequest(MustParseDevice("D300"), 3))
+	if expected := mustRequest(t)(station.BuildReadRequest("D", 300, 3)); request != expected {
+		t.Fatalf("expected %v but actual is %v", expected, request)
+	}
+	request2 := mustRequest(t)(station.BuildBitWriteDeviceRequest(MustParseDevice("X1F"), []bool{true}))
+	if expected2 := mustRequest(t)(station.BuildBitWriteRequest("X", 0x1F, []bool{true})); request2 != expected2 {
+		t.Fatalf("expected %v but actual is %v", expected2, request2)
+	}
+
+	if _, err := station.BuildBitReadDeviceRequest(MustParseDevice("D100.F"), 1); err == nil {
+		t.Fatalf("expected error of bit of word device but actual is nil")
+	}
+}
@@ -0,0 +1,410 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// STRUCT_TAG is struct tag key of device address like `mcp:"D100"`.
+const STRUCT_TAG = "mcp"
+
+// structField is struct field bound to device by struct tag.
+// tag is device address and options like `mcp:"D100"`, `mcp:"M10"`, `mcp:"D100.F,readonly"` or `mcp:"D300,len=10"`.
+type structField struct {
+	// index of field in struct
+	index int
+	// name of field
+	name string
+	// device of field
+	device Device
+	// bitDevice is true if device is bit device like M. offset of bit device is bit units.
+	bitDevice bool
+	// size is number of words of field. bool is 0.
+	size int64
+	// readonly field is not written by WriteStruct.
+	readonly bool
+}
+
+// span returns head and length of field in units of device. word device is word units, and bit device is bit units.
+func (f structField) span() (int64, int64) {
+	if f.bitDevice {
+		if f.size == 0 {
+			return f.device.Offset, 1
+		}
+		return f.device.Offset, 16 * f.size
+	}
+	if f.size == 0 {
+		return f.device.Offset, 1 // bit of word device
+	}
+	return f.device.Offset, f.size
+}
+
+// structBlock is device range that is read by one ReadWords.
+type structBlock struct {
+	// head and end of block in units of device
+	start, end int64
+	fields     []structField
+}
+
+// ReadStruct reads devices bound to fields of struct by struct tag like `mcp:"D100"`.
+// ptr must be pointer to struct. Fields are grouped by device and read by the minimum number of reads.
+// Supported field types are bool, int16, uint16, int32, uint32, float32, float64 and string.
+// bool field is bit device like M10 or bit of word device like D100.F.
+// string field requires number of words like `mcp:"D300,len=10"`.
+func (t Typed) ReadStruct(ptr interface{}) error {
+	v, fields, err := t.structFields(ptr)
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groupStructFields(fields) {
+		maxSpan := t.maxWordPoints()
+		if group[0].bitDevice {
+			maxSpan *= 16
+		}
+
+		for _, b := range planStructBlocks(group, maxSpan) {
+			numWords := b.end - b.start
+			if group[0].bitDevice {
+				numWords = (numWords + 15) / 16
+			}
+			words, err := t.rw.ReadWords(group[0].device.Name, b.start, numWords)
+			if err != nil {
+				return err
+			}
+			for _, f := range b.fields {
+				if err := setStructField(v.Field(f.index), f, words, b.start); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// WriteStruct writes fields of struct to devices bound by struct tag like `mcp:"D100"`.
+// ptr must be pointer to struct. contiguous fields are written by one write, and devices between fields are not written.
+// readonly fields like `mcp:"D100,readonly"` are not written. bit of word device like D100.F must be readonly.
+func (t Typed) WriteStruct(ptr interface{}) error {
+	v, fields, err := t.structFields(ptr)
+	if err != nil {
+		return err
+	}
+	// values are checked before any write, so that struct is not written partially
+	for _, f := range fields {
+		if err := validateStructValue(v.Field(f.index), f); err != nil {
+			return err
+		}
+	}
+
+	for _, group := range groupStructFields(fields) {
+		var writable []structField
+		for _, f := range group {
+			if f.readonly {
+				continue
+			}
+			if f.device.HasBit {
+				return fmt.Errorf("field %v of bit of word device %v must be readonly", f.name, f.device)
+			}
+			writable = append(writable, f)
+		}
+
+		// contiguous fields are written together
+		for i := 0; i < len(writable); {
+			start, n := writable[i].span()
+			end := start + n
+			words := structFieldWords(v.Field(writable[i].index), writable[i])
+			bits := structFieldBits(v.Field(writable[i].index), writable[i])
+			j := i + 1
+			for ; j < len(writable); j++ {
+				s, n := writable[j].span()
+				if s < end {
+					return fmt.Errorf("field %v and %v overlap", writable[j-1].name, writable[j].name)
+				}
+				if s != end {
+					break
+				}
+				end = s + n
+				words = append(words, structFieldWords(v.Field(writable[j].index), writable[j])...)
+				bits = append(bits, structFieldBits(v.Field(writable[j].index), writable[j])...)
+			}
+
+			name := writable[i].device.Name
+			if writable[i].bitDevice {
+				err = t.rw.WriteBits(name, start, bits)
+			} else {
+				err = t.rw.WriteWords(name, start, words)
+			}
+			if err != nil {
+				return err
+			}
+			i = j
+		}
+	}
+	return nil
+}
+
+// structFields returns struct value and fields bound to device by struct tag.
+func (t Typed) structFields(ptr interface{}) (reflect.Value, []structField, error) {
+	pv := reflect.ValueOf(ptr)
+	if pv.Kind() != reflect.Ptr || pv.IsNil() || pv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, nil, errors.New("argument must be non nil pointer to struct")
+	}
+	v := pv.Elem()
+
+	var fields []structField
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		tag, ok := sf.Tag.Lookup(STRUCT_TAG)
+		if !ok || tag == "-" {
+			continue
+		}
+		if sf.PkgPath != "" {
+			return reflect.Value{}, nil, fmt.Errorf("field %v is not exported", sf.Name)
+		}
+
+		f, err := t.parseStructTag(sf, tag)
+		if err != nil {
+			return reflect.Value{}, nil, err
+		}
+		f.index = i
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return reflect.Value{}, nil, fmt.Errorf("struct %v has no field with %v tag", v.Type(), STRUCT_TAG)
+	}
+	return v, fields, nil
+}
+
+// parseStructTag parses struct tag of field.
+func (t Typed) parseStructTag(sf reflect.StructField, tag string) (structField, error) {
+	items := strings.Split(tag, ",")
+	device, err := t.parseDevice(items[0])
+	if err != nil {
+		return structField{}, fmt.Errorf("field %v: %w", sf.Name, err)
+	}
+	name, _, err := parseExtendedName(device.Name)
+	if err != nil {
+		return structField{}, fmt.Errorf("field %v: %w", sf.Name, err)
+	}
+
+	f := structField{
+		name:      sf.Name,
+		device:    device,
+		bitDevice: deviceCatalogue[name].Type == BitDevice,
+	}
+
+	for _, opt := range items[1:] {
+		switch {
+		case opt == "readonly":
+			f.readonly = true
+		case strings.HasPrefix(opt, "len="):
+			n, err := strconv.ParseInt(strings.TrimPrefix(opt, "len="), 10, 64)
+			if err != nil || n < 1 {
+				return structField{}, fmt.Errorf("field %v: invalid len option %v", sf.Name, opt)
+			}
+			f.size = n
+		default:
+			return structField{}, fmt.Errorf("field %v: unknown option %v", sf.Name, opt)
+		}
+	}
+
+	switch sf.Type.Kind() {
+	case reflect.Bool:
+		if !f.bitDevice && !device.HasBit {
+			return structField{}, fmt.Errorf("field %v: bool field requires bit device or bit of word device like D100.F", sf.Name)
+		}
+		f.size = 0
+		return f, nil
+	case reflect.Int16, reflect.Uint16:
+		f.size = 1
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		f.size = 2
+	case reflect.Float64:
+		f.size = 4
+	case reflect.String:
+		if f.size == 0 {
+			return structField{}, fmt.Errorf("field %v: string field requires len option like %v,len=10", sf.Name, items[0])
+		}
+	default:
+		return structField{}, fmt.Errorf("field %v: type %v is not supported", sf.Name, sf.Type)
+	}
+	if device.HasBit {
+		return structField{}, fmt.Errorf("field %v: bit of word device %v requires bool field", sf.Name, device)
+	}
+	return f, nil
+}
+
+// groupStructFields groups fields by device name, and sorts fields of each group by offset.
+func groupStructFields(fields []structField) [][]structField {
+	groups := map[string][]structField{}
+	var names []string
+	for _, f := range fields {
+		if _, ok := groups[f.device.Name]; !ok {
+			names = append(names, f.device.Name)
+		}
+		groups[f.device.Name] = append(groups[f.device.Name], f)
+	}
+	sort.Strings(names)
+
+	results := make([][]structField, 0, len(names))
+	for _, name := range names {
+		group := groups[name]
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].device.Offset < group[j].device.Offset
+		})
+		results = append(results, group)
+	}
+	return results
+}
+
+// planStructBlocks merges sorted fields into blocks that span is less than or equal to maxSpan.
+// head of bit device block is aligned to multiple of 16, because bit device is read as word units.
+func planStructBlocks(fields []structField, maxSpan int64) []structBlock {
+	var blocks []structBlock
+	for _, f := range fields {
+		s, n := f.span()
+		if len(blocks) > 0 {
+			b := &blocks[len(blocks)-1]
+			if s+n-b.start <= maxSpan || s+n <= b.end {
+				if s+n > b.end {
+					b.end = s + n
+				}
+				b.fields = append(b.fields, f)
+				continue
+			}
+		}
+
+		start := s
+		if f.bitDevice {
+			start = s &^ 15
+		}
+		blocks = append(blocks, structBlock{start: start, end: s + n, fields: []structField{f}})
+	}
+	return blocks
+}
+
+// setStructField sets value of field from words read from start.
+func setStructField(v reflect.Value, f structField, words []uint16, start int64) error {
+	bit := func(i int64) bool {
+		return words[i/16]>>(i%16)&1 == 1
+	}
+
+	idx := f.device.Offset - start
+	if v.Kind() == reflect.Bool {
+		if f.bitDevice {
+			v.SetBool(bit(idx))
+		} else {
+			v.SetBool(words[idx]>>f.device.Bit&1 == 1)
+		}
+		return nil
+	}
+
+	// field words
+	fieldWords := make([]uint16, f.size)
+	for w := range fieldWords {
+		if !f.bitDevice {
+			fieldWords[w] = words[idx+int64(w)]
+			continue
+		}
+		for i := int64(0); i < 16; i++ {
+			if bit(idx + 16*int64(w) + i) {
+				fieldWords[w] |= 1 << i
+			}
+		}
+	}
+
+	// value is stored from low word
+	var u uint64
+	for w := len(fieldWords) - 1; w >= 0 && w < 4; w-- {
+		u = u<<16 | uint64(fieldWords[w])
+	}
+
+	switch v.Kind() {
+	case reflect.Int16:
+		v.SetInt(int64(int16(u)))
+	case reflect.Int32:
+		v.SetInt(int64(int32(u)))
+	case reflect.Uint16, reflect.Uint32:
+		v.SetUint(u)
+	case reflect.Float32:
+		v.SetFloat(float64(math.Float32frombits(uint32(u))))
+	case reflect.Float64:
+		v.SetFloat(math.Float64frombits(u))
+	case reflect.String:
+		b := make([]byte, 0, 2*len(fieldWords))
+		for _, w := range fieldWords {
+			b = append(b, byte(w), byte(w>>8))
+		}
+		s := string(b)
+		if i := strings.IndexByte(s, 0); i >= 0 {
+			s = s[:i]
+		}
+		v.SetString(s)
+	default:
+		return fmt.Errorf("field %v: type %v is not supported", f.name, v.Type())
+	}
+	return nil
+}
+
+// validateStructValue checks that value of field can be written to the devices bound to the field.
+func validateStructValue(v reflect.Value, f structField) error {
+	if f.readonly || v.Kind() != reflect.String {
+		return nil
+	}
+	if n := len(v.String()); int64(n) > 2*f.size {
+		return fmt.Errorf("field %v: string must be less than or equal to %v byte but actual is %v byte", f.name, 2*f.size, n)
+	}
+	return nil
+}
+
+// structFieldWords returns value of word field as words stored from low word. string is padded with NUL.
+func structFieldWords(v reflect.Value, f structField) []uint16 {
+	var u uint64
+	switch v.Kind() {
+	case reflect.Bool:
+		return nil
+	case reflect.Int16, reflect.Int32:
+		u = uint64(v.Int())
+	case reflect.Uint16, reflect.Uint32:
+		u = v.Uint()
+	case reflect.Float32:
+		u = uint64(math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		u = math.Float64bits(v.Float())
+	case reflect.String:
+		b := make([]byte, 2*f.size)
+		copy(b, v.String())
+		words := make([]uint16, f.size)
+		for i := range words {
+			words[i] = uint16(b[2*i]) | uint16(b[2*i+1])<<8
+		}
+		return words
+	}
+
+	words := make([]uint16, f.size)
+	for i := range words {
+		words[i] = uint16(u >> (16 * i))
+	}
+	return words
+}
+
+// structFieldBits returns value of field on bit device as bit units. word is 16 points from lower bit.
+func structFieldBits(v reflect.Value, f structField) []bool {
+	if v.Kind() == reflect.Bool {
+		return []bool{v.Bool()}
+	}
+
+	var bits []bool
+	for _, w := range structFieldWords(v, f) {
+		for i := 0; i < 16; i++ {
+			bits = append(bits, w>>i&1 == 1)
+		}
+	}
+	return bits
+}
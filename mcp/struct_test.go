@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testCell struct {
+	Count   int32   `mcp:"D100"`
+	Speed   uint16  `mcp:"D102"`
+	Temp    float32 `mcp:"D200"`
+	Running bool    `mcp:"M10"`
+	Alarm   bool    `mcp:"M11"`
+	Name    string  `mcp:"D300,len=4"`
+	Ready   bool    `mcp:"D400.1,readonly"`
+	Comment string
+}
+
+func TestTyped_Struct(t *testing.T) {
+	plc := startMemoryPLC(t)
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	typed := NewTyped(client)
+
+	written := testCell{Count: -5, Speed: 1200, Temp: 36.5, Running: true, Name: "CELL1", Ready: true, Comment: "not bound"}
+	if err := typed.WriteStruct(&written); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+	// D100-D103, D200-D201, M10-M11 and D300-D303. readonly D400.1 is not written
+	if plc.requests() != 4 {
+		t.Fatalf("expected %v but actual is %v", 4, plc.requests())
+	}
+	words, _ := typed.ReadUint16s("D", 300, 4)
+	if diff := cmp.Diff([]uint16{0x4543, 0x4C4C, 0x0031, 0x0000}, words); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+
+	if err := typed.WriteUint16s("D", 400, []uint16{0x0002}); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+
+	before := plc.requests()
+	var read testCell
+	if err := typed.ReadStruct(&read); err != nil {
+		t.Fatalf("unexpected read err: %v", err)
+	}
+	// D100-D400 is read by one request, and M0-M15 by one request
+	if plc.requests()-before != 2 {
+		t.Fatalf("expected %v but actual is %v", 2, plc.requests()-before)
+	}
+	expected := written
+	expected.Comment = ""
+	if diff := cmp.Diff(expected, read); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+}
+
+func TestTyped_StructSplit(t *testing.T) {
+	plc := startMemoryPLC(t)
+	defer plc.Close()
+
+	profile := ProfileOf(SeriesQ)
+	profile.MaxWordPoints = 10
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation(), WithProfile(profile))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	typed := NewTyped(client)
+
+	var v struct {
+		A uint16 `mcp:"D0"`
+		B uint16 `mcp:"D9"`
+		C uint16 `mcp:"D10"`
+	}
+	if err := typed.ReadStruct(&v); err != nil {
+		t.Fatalf("unexpected read err: %v", err)
+	}
+	// D0-D9 and D10 are read separately because of max word points
+	if plc.requests() != 2 {
+		t.Fatalf("expected %v but actual is %v", 2, plc.requests())
+	}
+}
+
+func TestTyped_Struct1E(t *testing.T) {
+	// head device of word read of 1E frame is 4byte from index 4. all bits of response words are on
+	var head int32 = -1
+	plc := startTCPPLC(t, func(req []byte) []byte {
+		atomic.StoreInt32(&head, int32(binary.LittleEndian.Uint32(req[4:8])))
+		return []byte{0x81, 0x00, 0xFF, 0xFF}
+	})
+	defer plc.Close()
+
+	client, err := New1EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation1E())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	typed := NewTyped(client)
+
+	// X17 of MELSEC-F is octal, so it is X15 in decimal
+	var v struct {
+		Input bool `mcp:"X17"`
+	}
+	if err := typed.ReadStruct(&v); err != nil {
+		t.Fatalf("unexpected read err: %v", err)
+	}
+	if h := atomic.LoadInt32(&head); h != 0 {
+		t.Fatalf("expected %v but actual is %v", 0, h)
+	}
+	if !v.Input {
+		t.Fatalf("expected %v but actual is %v", true, v.Input)
+	}
+}
+
+func TestTyped_StructError(t *testing.T) {
+	client, err := New3EClient("127.0.0.1", 5000, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	typed := NewTyped(client)
+
+	var wordBool struct {
+		A bool `mcp:"D100"`
+	}
+	var noLen struct {
+		A string `mcp:"D100"`
+	}
+	var bitOfWord struct {
+		A uint16 `mcp:"D100.1"`
+	}
+	var unsupported struct {
+		A int `mcp:"D100"`
+	}
+	var unexported struct {
+		a uint16 `mcp:"D100"`
+	}
+	var unknownOption struct {
+		A uint16 `mcp:"D100,foo"`
+	}
+	var writableBit struct {
+		A bool `mcp:"D100.1"`
+	}
+	var overlap struct {
+		A int32  `mcp:"D100"`
+		B uint16 `mcp:"D101"`
+	}
+
+	cases := []struct {
+		name string
+		err  error
+	}{
+		{name: "not pointer", err: typed.ReadStruct(wordBool)},
+		{name: "bool of word device", err: typed.ReadStruct(&wordBool)},
+		{name: "string without len", err: typed.ReadStruct(&noLen)},
+		{name: "bit of word device to uint16", err: typed.ReadStruct(&bitOfWord)},
+		{name: "unsupported type", err: typed.ReadStruct(&unsupported)},
+		{name: "unexported field", err: typed.ReadStruct(&unexported)},
+		{name: "unknown option", err: typed.ReadStruct(&unknownOption)},
+		{name: "writable bit of word device", err: typed.WriteStruct(&writableBit)},
+		{name: "overlap", err: typed.WriteStruct(&overlap)},
+	}
+	for _, v := range cases {
+		if v.err == nil {
+			t.Errorf("%v: expected error but actual is nil", v.name)
+		}
+	}
+
+	// longer string is error like WriteString, and nothing is sent
+	longString := struct {
+		Name string `mcp:"D300,len=2"`
+	}{Name: "CELL1"}
+	if err := typed.WriteStruct(&longString); err == nil || !strings.Contains(err.Error(), "string must be less than or equal to 4 byte") {
+		t.Errorf("expected error of long string but actual is %v", err)
+	}
+}
@@ -0,0 +1,351 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// UDP_DEFAULT_TIMEOUT is default time to wait response datagram. it is longer than MONITORING_TIMER.
+	UDP_DEFAULT_TIMEOUT = 5 * time.Second
+	// UDP_READ_BUFFER_SIZE is enough size for one mc protocol response datagram.
+	UDP_READ_BUFFER_SIZE = 8192
+
+	// MONITORING_TIMER_UNIT is unit of monitoring timer of request.
+	MONITORING_TIMER_UNIT = 250 * time.Millisecond
+	// RESPONSE_TIMEOUT_MARGIN is time to wait response after monitoring timer expires.
+	// plc returns error end code when monitoring timer expires, so no response after the margin means that plc does not respond.
+	RESPONSE_TIMEOUT_MARGIN = 2 * time.Second
+)
+
+var (
+	// ErrTimeout is returned when response is not received from plc within timeout.
+	ErrTimeout = errors.New("mcp: response timeout")
+	// ErrPacketLost is returned when received udp datagram is not complete response frame.
+	ErrPacketLost = errors.New("mcp: packet lost")
+)
+
+// transport sends request frame to remote plc and receives response frame.
+// If ctx is canceled or its deadline is exceeded, ctx.Err() is returned.
+type transport interface {
+	// roundTrip sends payload and returns received response frame.
+	// readSize is expected size of response frame. see frameReader.
+	roundTrip(ctx context.Context, payload []byte, readSize int64) ([]byte, error)
+	// roundTrips sends each payload on one connection and returns received response frames in the same order.
+	// readSizes are expected size of response frame of each payload.
+	roundTrips(ctx context.Context, payloads [][]byte, readSizes []int64) ([][]byte, error)
+	// state returns state of connection held between requests.
+	state() ConnState
+	// generation returns number of connections dialed so far. It changes when state kept by ethernet module for the connection is lost.
+	generation() uint64
+	// disconnect closes connection held between requests, and next request dials again.
+	disconnect()
+	// close closes connection held between requests.
+	close() error
+}
+
+// newTransport returns transport to remote plc. readFrame reads response frame of the frame type of client.
+// monitoringTimer is monitoring timer of request like MONITORING_TIMER, and tcp transport waits response until it expires.
+// If persistent is true, tcp connection is held between requests.
+// If pipelining is also true, requests are sent on the connection without waiting response of previous request.
+// Requests wait in queue until number of in-flight requests is less than the maximum of options.
+func newTransport(host string, port int, o *options, readFrame frameReader, monitoringTimer string, persistent, pipelining bool) (transport, error) {
+	if o.udp {
+		udpAddr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("%v:%v", host, port))
+		if err != nil {
+			return nil, err
+		}
+		return &queuedTransport{
+			transport: &udpTransport{udpAddr: udpAddr, timeout: o.udpTimeout, readFrame: readFrame},
+			queue:     newRequestQueue(o.maxInFlight),
+		}, nil
+	}
+
+	tcpAddr, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("%v:%v", host, port))
+	if err != nil {
+		return nil, err
+	}
+	if persistent {
+		return newPersistentTransport(tcpAddr, responseTimeout(monitoringTimer), readFrame, o, pipelining), nil
+	}
+	return &queuedTransport{
+		transport: &tcpTransport{tcpAddr: tcpAddr, timeout: responseTimeout(monitoringTimer), readFrame: readFrame},
+		queue:     newRequestQueue(o.maxInFlight),
+	}, nil
+}
+
+// responseTimeout returns time to wait response of request that has monitoringTimer.
+// monitoringTimer is binary mode expression of 250[msec] units like "1000". 0 is infinite wait, and then 0 is returned.
+func responseTimeout(monitoringTimer string) time.Duration {
+	v, err := Binary.fieldValue(monitoringTimer)
+	if err != nil || v == 0 {
+		return 0
+	}
+	return time.Duration(v)*MONITORING_TIMER_UNIT + RESPONSE_TIMEOUT_MARGIN
+}
+
+// roundTripDeadline returns earlier time of now+timeout and deadline of ctx. timeout 0 is no limit.
+func roundTripDeadline(ctx context.Context, timeout time.Duration) time.Time {
+	deadline, ok := ctx.Deadline()
+	if timeout > 0 {
+		if limit := time.Now().Add(timeout); !ok || limit.Before(deadline) {
+			return limit
+		}
+	}
+	return deadline // zero value if ctx has no deadline
+}
+
+// watchContext sets past deadline by setDeadline when ctx is done, so that blocking write or read of conn returns.
+// returned function stops watching.
+func watchContext(ctx context.Context, setDeadline func(time.Time) error) func() {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-ctx.Done():
+			_ = setDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// transportError returns ctx.Err() if err is caused by ctx, or ErrTimeout if err is timeout of transport.
+func transportError(ctx context.Context, err error, addr net.Addr, timeout time.Duration) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		// deadline of conn may expire just before deadline of ctx
+		if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+			return context.DeadlineExceeded
+		}
+		return fmt.Errorf("%w: no response from %v within %v", ErrTimeout, addr, timeout)
+	}
+	return err
+}
+
+// noResponseError is returned when request is sent but its response is not received, like closed connection or timeout.
+// It is transparent for errors.Is and errors.As.
+type noResponseError struct {
+	err error
+}
+
+func (e *noResponseError) Error() string {
+	return e.err.Error()
+}
+
+func (e *noResponseError) Unwrap() error {
+	return e.err
+}
+
+// isNoResponse returns true if request is sent but its response is not received.
+func isNoResponse(err error) bool {
+	var e *noResponseError
+	return errors.As(err, &e)
+}
+
+// responseError returns error of reading response. If connection is closed or timeout before the complete response,
+// it is noResponseError. invalid response frame is not.
+func responseError(ctx context.Context, err error, addr net.Addr, timeout time.Duration) error {
+	var netErr net.Error
+	if errors.Is(err, ErrFrameTruncated) || errors.As(err, &netErr) || ctx.Err() != nil {
+		return &noResponseError{err: transportError(ctx, err, addr, timeout)}
+	}
+	return transportError(ctx, err, addr, timeout)
+}
+
+// tcpTransport dials to remote plc for each request.
+// response frame may be received in several segments, so it is read by length of the frame.
+type tcpTransport struct {
+	// number of dialed connections. it is first field for 64bit atomic access
+	dials uint64
+	// PLC address
+	tcpAddr *net.TCPAddr
+	// time to wait response. 0 is no limit
+	timeout time.Duration
+	// reader of response frame
+	readFrame frameReader
+}
+
+func (t *tcpTransport) roundTrip(ctx context.Context, payload []byte, readSize int64) ([]byte, error) {
+	resps, err := t.roundTrips(ctx, [][]byte{payload}, []int64{readSize})
+	if err != nil {
+		return nil, err
+	}
+	return resps[0], nil
+}
+
+// roundTrips dials and sends each payload. deadline of each request is timeout or deadline of ctx.
+func (t *tcpTransport) roundTrips(ctx context.Context, payloads [][]byte, readSizes []int64) ([][]byte, error) {
+	dialer := &net.Dialer{Deadline: roundTripDeadline(ctx, t.timeout)}
+	conn, err := dialer.DialContext(ctx, "tcp", t.tcpAddr.String())
+	if err != nil {
+		return nil, transportError(ctx, err, t.tcpAddr, t.timeout)
+	}
+	defer conn.Close()
+	atomic.AddUint64(&t.dials, 1)
+	defer watchContext(ctx, conn.SetDeadline)()
+
+	resps := make([][]byte, 0, len(payloads))
+	for i, payload := range payloads {
+		if err := conn.SetDeadline(roundTripDeadline(ctx, t.timeout)); err != nil {
+			return nil, err
+		}
+		// deadline above overrides past deadline set by watchContext if ctx is done before
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Send message
+		if _, err = conn.Write(payload); err != nil {
+			return nil, transportError(ctx, err, t.tcpAddr, t.timeout)
+		}
+
+		// Receive message
+		resp, err := t.readFrame(conn, readSizes[i])
+		if err != nil {
+			return nil, responseError(ctx, err, t.tcpAddr, t.timeout)
+		}
+		resps = append(resps, resp)
+	}
+	return resps, nil
+}
+
+// state of tcpTransport is always ConnDisconnected because connection is closed after each request.
+func (t *tcpTransport) state() ConnState {
+	return ConnDisconnected
+}
+
+// generation of tcpTransport is incremented for each request because each request dials new connection.
+func (t *tcpTransport) generation() uint64 {
+	return atomic.LoadUint64(&t.dials)
+}
+
+func (t *tcpTransport) disconnect() {}
+
+func (t *tcpTransport) close() error {
+	return nil
+}
+
+// udpTransport sends each request as one datagram.
+type udpTransport struct {
+	// PLC address
+	udpAddr *net.UDPAddr
+	// time to wait response datagram
+	timeout time.Duration
+	// reader of response frame
+	readFrame frameReader
+}
+
+func (t *udpTransport) roundTrip(ctx context.Context, payload []byte, readSize int64) ([]byte, error) {
+	resps, err := t.roundTrips(ctx, [][]byte{payload}, []int64{readSize})
+	if err != nil {
+		return nil, err
+	}
+	return resps[0], nil
+}
+
+// roundTrips sends each payload as one datagram from same local port and returns the datagram from remote plc.
+// datagrams from other peers are ignored. If no datagram is received from remote plc within timeout, ErrTimeout is returned.
+// datagram must be one complete response frame. If it is incomplete, ErrPacketLost is returned.
+func (t *udpTransport) roundTrips(ctx context.Context, payloads [][]byte, readSizes []int64) ([][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	defer watchContext(ctx, conn.SetDeadline)()
+
+	resps := make([][]byte, 0, len(payloads))
+	for i, payload := range payloads {
+		datagram, err := t.exchange(ctx, conn, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		r := bytes.NewReader(datagram)
+		resp, err := t.readFrame(r, readSizes[i])
+		if errors.Is(err, ErrFrameTruncated) {
+			return nil, fmt.Errorf("%w: %v", ErrPacketLost, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.Len() > 0 {
+			return nil, fmt.Errorf("%w: datagram is %v byte but response frame is %v byte", ErrFrameTooLarge, len(datagram), len(resp))
+		}
+		resps = append(resps, resp)
+	}
+	return resps, nil
+}
+
+// exchange sends payload as one datagram and receives the datagram from remote plc.
+func (t *udpTransport) exchange(ctx context.Context, conn *net.UDPConn, payload []byte) ([]byte, error) {
+	if err := conn.SetDeadline(roundTripDeadline(ctx, t.timeout)); err != nil {
+		return nil, err
+	}
+	// deadline above overrides past deadline set by watchContext if ctx is done before
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Send message
+	writeLen, err := conn.WriteToUDP(payload, t.udpAddr)
+	if err != nil {
+		return nil, transportError(ctx, err, t.udpAddr, t.timeout)
+	}
+	if writeLen != len(payload) {
+		return nil, fmt.Errorf("request datagram is truncated: %v of %v byte is sent", writeLen, len(payload))
+	}
+
+	// Receive message
+	readBuff := make([]byte, UDP_READ_BUFFER_SIZE)
+	for {
+		readLen, from, err := conn.ReadFromUDP(readBuff)
+		if err != nil {
+			return nil, responseError(ctx, err, t.udpAddr, t.timeout)
+		}
+
+		// ignore datagram that is not from remote plc
+		if !from.IP.Equal(t.udpAddr.IP) || from.Port != t.udpAddr.Port {
+			continue
+		}
+		return readBuff[:readLen], nil
+	}
+}
+
+// state of udpTransport is always ConnDisconnected because udp has no connection.
+func (t *udpTransport) state() ConnState {
+	return ConnDisconnected
+}
+
+// generation of udpTransport is always 0 because udp has no connection.
+func (t *udpTransport) generation() uint64 {
+	return 0
+}
+
+func (t *udpTransport) disconnect() {}
+
+func (t *udpTransport) close() error {
+	return nil
+}
@@ -0,0 +1,599 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// TCP_KEEP_ALIVE_PERIOD is default period of tcp keep-alive probe of persistent connection.
+	// keep-alive detects half-open connection that remote plc is gone without closing it.
+	TCP_KEEP_ALIVE_PERIOD = 10 * time.Second
+	// RECONNECT_MIN_BACKOFF is default time to wait before dial after the first dial failure.
+	RECONNECT_MIN_BACKOFF = 100 * time.Millisecond
+	// RECONNECT_MAX_BACKOFF is default maximum time to wait before dial. backoff is doubled for each dial failure.
+	RECONNECT_MAX_BACKOFF = 10 * time.Second
+)
+
+// ErrClosed is returned when request is sent by closed client.
+var ErrClosed = errors.New("mcp: client is closed")
+
+// errDisconnected is returned to in-flight requests when connection is closed by client on purpose.
+var errDisconnected = errors.New("mcp: connection is closed by client")
+
+type idempotentKey struct{}
+
+// contextWithIdempotent returns ctx of request that plc can execute again without side effect like read.
+func contextWithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// isIdempotent returns true if ctx is of contextWithIdempotent.
+func isIdempotent(ctx context.Context) bool {
+	idempotent, _ := ctx.Value(idempotentKey{}).(bool)
+	return idempotent
+}
+
+// ConnState is state of persistent connection to plc.
+type ConnState int
+
+const (
+	// ConnDisconnected is state that client has no connection. next request dials to plc.
+	ConnDisconnected ConnState = iota
+	// ConnConnecting is state that client is dialing to plc.
+	ConnConnecting
+	// ConnConnected is state that client has connection to plc.
+	ConnConnected
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case ConnDisconnected:
+		return "disconnected"
+	case ConnConnecting:
+		return "connecting"
+	case ConnConnected:
+		return "connected"
+	}
+	return "unknown"
+}
+
+// ConnEvent is notified when state of persistent connection changes.
+type ConnEvent struct {
+	// State is new state of connection.
+	State ConnState
+	// Err is cause of ConnDisconnected like dial failure or broken connection. It is nil when connection is closed by client.
+	Err error
+	// Reconnect is true if ConnConnected is connection after the previous connection is lost.
+	Reconnect bool
+	// Failures is number of consecutive dial failures.
+	Failures int
+}
+
+// persistentTransport holds tcp connection to remote plc and sends requests on it.
+// If connection is lost, it dials again on the next request. After dial failure, next dial waits backoff.
+//
+// Requests wait in queue until they can be in-flight. If pipelining is true, next request is sent before response of
+// previous request, and responses are read in the order of requests by reader goroutine of the connection.
+// Otherwise next request is sent after response of previous request, because response of 3E frame can not be identified.
+type persistentTransport struct {
+	// number of dialed connections. it is first field for 64bit atomic access
+	dials uint64
+	// PLC address
+	tcpAddr *net.TCPAddr
+	// time to wait response. 0 is no limit
+	timeout time.Duration
+	// reader of response frame
+	readFrame frameReader
+	// period of tcp keep-alive probe
+	keepAlive time.Duration
+	// backoff before dial after dial failure
+	minBackoff, maxBackoff time.Duration
+	// queue of requests waiting to be in-flight
+	queue *requestQueue
+	// send next request before response of previous request
+	pipelining bool
+	// notifier of connection event
+	events *eventDispatcher
+
+	// sem is held while connection is dialed and request frames are written, so that frames of requests are not interleaved.
+	sem chan struct{}
+
+	// fields below are guarded by sem
+	pipe      *pipeline
+	connected bool
+	failures  int
+	nextDial  time.Time
+	closed    bool
+}
+
+func newPersistentTransport(tcpAddr *net.TCPAddr, timeout time.Duration, readFrame frameReader, o *options, pipelining bool) *persistentTransport {
+	maxInFlight := 1
+	if pipelining {
+		maxInFlight = o.maxInFlight
+	}
+	return &persistentTransport{
+		tcpAddr:    tcpAddr,
+		timeout:    timeout,
+		readFrame:  readFrame,
+		keepAlive:  o.keepAlive,
+		minBackoff: o.minBackoff,
+		maxBackoff: o.maxBackoff,
+		queue:      newRequestQueue(maxInFlight),
+		pipelining: pipelining,
+		events:     &eventDispatcher{handler: o.onConnEvent},
+		sem:        make(chan struct{}, 1),
+	}
+}
+
+func (t *persistentTransport) roundTrip(ctx context.Context, payload []byte, readSize int64) ([]byte, error) {
+	resps, err := t.roundTrips(ctx, [][]byte{payload}, []int64{readSize})
+	if err != nil {
+		return nil, err
+	}
+	return resps[0], nil
+}
+
+// roundTrips sends each payload on the persistent connection. If there is no connection, it dials first.
+// If reused connection is closed by plc before response, idempotent request of contextWithIdempotent is sent again on new connection once.
+// Other requests like write return the error, because plc may have executed them before closing the connection.
+// If ctx is done before response, ctx.Err() is returned and the response is dropped when it is received.
+func (t *persistentTransport) roundTrips(ctx context.Context, payloads [][]byte, readSizes []int64) ([][]byte, error) {
+	resps, stale, err := t.exchange(ctx, payloads, readSizes)
+	if err != nil && stale && isIdempotent(ctx) {
+		// plc closed idle connection
+		resps, _, err = t.exchange(ctx, payloads, readSizes)
+	}
+	return resps, err
+}
+
+// exchange sends payloads after the request becomes in-flight, and waits the responses.
+// payloads are sent at once if pipelining, otherwise each payload is sent after response of previous payload.
+// stale is true if connection is closed by plc before the first response.
+func (t *persistentTransport) exchange(ctx context.Context, payloads [][]byte, readSizes []int64) ([][]byte, bool, error) {
+	if err := t.queue.acquire(ctx, priorityOf(ctx)); err != nil {
+		return nil, false, err
+	}
+	// events of dial are notified after the request leaves in-flight, so that handler can send request
+	defer t.events.deliver()
+	// request is in-flight until the last response is received, even if ctx is done before it
+	s := &inFlightSlot{refs: 1, queue: t.queue}
+	defer s.done()
+
+	batch := 1
+	if t.pipelining {
+		batch = len(payloads)
+	}
+
+	resps := make([][]byte, 0, len(payloads))
+	for start := 0; start < len(payloads); start += batch {
+		end := start + batch
+		if end > len(payloads) {
+			end = len(payloads)
+		}
+
+		reqs, stale, err := t.send(ctx, payloads[start:end], readSizes[start:end], s)
+		if err != nil {
+			return nil, stale && len(resps) == 0, err
+		}
+		for _, req := range reqs {
+			select {
+			case result := <-req.result:
+				if result.err != nil {
+					return nil, result.stale && len(resps) == 0, result.err
+				}
+				resps = append(resps, result.frame)
+			case <-ctx.Done():
+				return nil, false, &noResponseError{err: ctx.Err()}
+			}
+		}
+	}
+	return resps, false, nil
+}
+
+// send writes payloads on current connection, and returns requests waiting the responses.
+// stale is true if reused connection is closed by plc before payloads are sent.
+func (t *persistentTransport) send(ctx context.Context, payloads [][]byte, readSizes []int64, s *inFlightSlot) ([]*pendingRequest, bool, error) {
+	select {
+	case t.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+	// events are notified by exchange after in-flight slot is released
+	defer func() { <-t.sem }()
+
+	if t.closed {
+		return nil, false, ErrClosed
+	}
+
+	if t.pipe != nil && t.pipe.isBroken() {
+		t.pipe = nil
+	}
+	reused := t.pipe != nil
+	if !reused {
+		if err := t.dial(ctx); err != nil {
+			return nil, false, err
+		}
+	}
+	p := t.pipe
+
+	reqs := make([]*pendingRequest, 0, len(payloads))
+	for i, payload := range payloads {
+		req := &pendingRequest{readSize: readSizes[i], slot: s, result: make(chan pendingResult, 1)}
+		if err := p.push(req); err != nil {
+			// connection is broken by other request before this request is sent
+			return nil, true, err
+		}
+		reqs = append(reqs, req)
+
+		if err := t.write(ctx, p.conn, payload); err != nil {
+			err = transportError(ctx, err, t.tcpAddr, t.timeout)
+			// partial frame may be sent, so the connection can not be used anymore
+			p.fail(fmt.Errorf("connection is broken while sending request: %v", err), false)
+			return nil, reused && isConnClosed(ctx, err), err
+		}
+	}
+	return reqs, false, nil
+}
+
+// write writes payload to conn. deadline of write is timeout or deadline of ctx.
+func (t *persistentTransport) write(ctx context.Context, conn net.Conn, payload []byte) error {
+	if err := conn.SetWriteDeadline(roundTripDeadline(ctx, t.timeout)); err != nil {
+		return err
+	}
+	defer watchContext(ctx, conn.SetWriteDeadline)()
+
+	_, err := conn.Write(payload)
+	return err
+}
+
+// dial connects to remote plc and starts reader of the connection. If previous dial is failed, it waits backoff before dial.
+func (t *persistentTransport) dial(ctx context.Context) error {
+	if wait := time.Until(t.nextDial); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	t.events.add(ConnEvent{State: ConnConnecting, Failures: t.failures})
+	dialer := &net.Dialer{Deadline: roundTripDeadline(ctx, t.timeout), KeepAlive: t.keepAlive}
+	conn, err := dialer.DialContext(ctx, "tcp", t.tcpAddr.String())
+	if err != nil {
+		t.failures++
+		t.nextDial = time.Now().Add(t.backoff())
+		err = transportError(ctx, err, t.tcpAddr, t.timeout)
+		t.events.add(ConnEvent{State: ConnDisconnected, Err: err, Failures: t.failures})
+		return err
+	}
+
+	t.pipe = newPipeline(conn, t.events)
+	atomic.AddUint64(&t.dials, 1)
+	t.failures = 0
+	t.nextDial = time.Time{}
+	t.events.add(ConnEvent{State: ConnConnected, Reconnect: t.connected})
+	t.connected = true
+
+	go t.readLoop(t.pipe)
+	return nil
+}
+
+// readLoop reads response frames of pending requests of p in order until p is broken.
+// deadline of each response is timeout from the start of reading it.
+func (t *persistentTransport) readLoop(p *pipeline) {
+	// events of broken connection are notified on this goroutine
+	defer t.events.deliver()
+
+	received := false
+	for {
+		req := p.next()
+		if req == nil {
+			return
+		}
+
+		var deadline time.Time
+		if t.timeout > 0 {
+			deadline = time.Now().Add(t.timeout)
+		}
+		if err := p.conn.SetReadDeadline(deadline); err != nil {
+			p.fail(err, false)
+			return
+		}
+
+		r := &countingReader{r: p.conn}
+		frame, err := t.readFrame(r, req.readSize)
+		if err != nil {
+			// connection that has received response is closed by plc before the next response
+			stale := received && r.n == 0 && isConnClosed(context.Background(), err)
+			p.fail(responseError(context.Background(), err, t.tcpAddr, t.timeout), stale)
+			return
+		}
+		received = true
+		p.pop(req)
+		req.finish(frame, nil, false)
+	}
+}
+
+// backoff returns time to wait before next dial. it is doubled for each dial failure up to maxBackoff.
+func (t *persistentTransport) backoff() time.Duration {
+	backoff := t.minBackoff
+	for i := 1; i < t.failures && backoff < t.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > t.maxBackoff {
+		return t.maxBackoff
+	}
+	return backoff
+}
+
+// unlock releases sem, and then notifies events. handler can send request in it.
+func (t *persistentTransport) unlock() {
+	<-t.sem
+	t.events.deliver()
+}
+
+func (t *persistentTransport) state() ConnState {
+	return t.events.state()
+}
+
+// generation is incremented when new connection is dialed. state kept by ethernet module like monitor registration is lost on new connection.
+func (t *persistentTransport) generation() uint64 {
+	return atomic.LoadUint64(&t.dials)
+}
+
+// disconnect closes current connection on purpose. in-flight requests return errDisconnected.
+func (t *persistentTransport) disconnect() {
+	t.sem <- struct{}{}
+	defer t.unlock()
+
+	if t.pipe != nil {
+		t.pipe.fail(errDisconnected, false)
+		t.pipe = nil
+	}
+}
+
+// close closes connection. in-flight requests and requests after close return ErrClosed.
+func (t *persistentTransport) close() error {
+	t.sem <- struct{}{}
+	defer t.unlock()
+
+	t.closed = true
+	if t.pipe != nil {
+		t.pipe.fail(ErrClosed, false)
+		t.pipe = nil
+	}
+	return nil
+}
+
+// pipeline is tcp connection and requests waiting the responses on it in the order of requests.
+type pipeline struct {
+	conn   net.Conn
+	events *eventDispatcher
+	// signal notifies reader of new pending request
+	signal chan struct{}
+	// done is closed when the connection is broken
+	done chan struct{}
+
+	mu      sync.Mutex
+	pending []*pendingRequest
+	broken  bool
+	err     error
+}
+
+func newPipeline(conn net.Conn, events *eventDispatcher) *pipeline {
+	return &pipeline{
+		conn:   conn,
+		events: events,
+		signal: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// push adds request that waits response. It must be called before the request is written.
+func (p *pipeline) push(req *pendingRequest) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.broken {
+		return p.err
+	}
+	req.slot.add()
+	p.pending = append(p.pending, req)
+
+	select {
+	case p.signal <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// next waits and returns the first pending request. It returns nil if connection is broken.
+func (p *pipeline) next() *pendingRequest {
+	for {
+		p.mu.Lock()
+		if p.broken {
+			p.mu.Unlock()
+			return nil
+		}
+		if len(p.pending) > 0 {
+			req := p.pending[0]
+			p.mu.Unlock()
+			return req
+		}
+		p.mu.Unlock()
+
+		select {
+		case <-p.signal:
+		case <-p.done:
+		}
+	}
+}
+
+// pop removes the first pending request after its response is received.
+func (p *pipeline) pop(req *pendingRequest) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.pending) > 0 && p.pending[0] == req {
+		p.pending = p.pending[1:]
+	}
+}
+
+func (p *pipeline) isBroken() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.broken
+}
+
+// fail closes the connection by err, and pending requests return err.
+// stale is true if plc has not processed pending requests, so they can be sent again.
+// ConnDisconnected event is added before the connection is marked broken, so that it precedes events of next connection.
+func (p *pipeline) fail(err error, stale bool) {
+	p.mu.Lock()
+	if p.broken {
+		p.mu.Unlock()
+		return
+	}
+
+	// connection closed by client has no cause
+	eventErr := err
+	if errors.Is(err, ErrClosed) || errors.Is(err, errDisconnected) {
+		eventErr = nil
+	}
+	p.events.add(ConnEvent{State: ConnDisconnected, Err: eventErr})
+
+	p.broken = true
+	p.err = err
+	reqs := p.pending
+	p.pending = nil
+	p.mu.Unlock()
+
+	_ = p.conn.Close()
+	close(p.done)
+	for _, req := range reqs {
+		req.finish(nil, err, stale)
+	}
+}
+
+// pendingRequest is request frame that is sent and waits the response.
+type pendingRequest struct {
+	// expected size of response frame
+	readSize int64
+	// in-flight slot of the request
+	slot *inFlightSlot
+	// result receives the response only once
+	result chan pendingResult
+}
+
+type pendingResult struct {
+	frame []byte
+	err   error
+	stale bool
+}
+
+// finish sends result to the waiting request.
+func (r *pendingRequest) finish(frame []byte, err error, stale bool) {
+	r.result <- pendingResult{frame: frame, err: err, stale: stale}
+	r.slot.done()
+}
+
+// inFlightSlot is in-flight slot of request queue. It is released when the request and all of its pending frames are done.
+type inFlightSlot struct {
+	refs  int32
+	queue *requestQueue
+}
+
+func (s *inFlightSlot) add() {
+	atomic.AddInt32(&s.refs, 1)
+}
+
+func (s *inFlightSlot) done() {
+	if atomic.AddInt32(&s.refs, -1) == 0 {
+		s.queue.release()
+	}
+}
+
+// eventDispatcher notifies connection events to handler in order.
+// events are added while the connection is locked, and delivered after it is unlocked, so that handler can send request.
+type eventDispatcher struct {
+	// handler of connection event. it may be nil
+	handler func(ConnEvent)
+	// current state. it is changed when event is added
+	current int32
+
+	mu         sync.Mutex
+	events     []ConnEvent
+	delivering bool
+}
+
+// add changes current state, and keeps event until deliver.
+func (d *eventDispatcher) add(event ConnEvent) {
+	atomic.StoreInt32(&d.current, int32(event.State))
+	if d.handler == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.events = append(d.events, event)
+}
+
+// deliver calls handler with kept events. If other goroutine is delivering, events are delivered by it.
+func (d *eventDispatcher) deliver() {
+	if d.handler == nil {
+		return
+	}
+
+	d.mu.Lock()
+	if d.delivering {
+		d.mu.Unlock()
+		return
+	}
+	d.delivering = true
+	for len(d.events) > 0 {
+		event := d.events[0]
+		d.events = d.events[1:]
+		d.mu.Unlock()
+		d.handler(event)
+		d.mu.Lock()
+	}
+	d.delivering = false
+	d.mu.Unlock()
+}
+
+func (d *eventDispatcher) state() ConnState {
+	return ConnState(atomic.LoadInt32(&d.current))
+}
+
+// isConnClosed returns true if err means that connection is closed or reset by peer, not timeout or cancel.
+func isConnClosed(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return false
+	}
+	return true
+}
+
+// countingReader counts received bytes.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *countingReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.n += n
+	return n, err
+}
@@ -0,0 +1,432 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// connPLC is fake plc that counts accepted connections and returns resp for each request.
+type connPLC struct {
+	net.Listener
+	// number of accepted connections
+	accepts int32
+}
+
+// startConnPLC starts connPLC. If closeIdle is true, plc closes connection after each response like idle timeout of ethernet module.
+func startConnPLC(t *testing.T, resp []byte, closeIdle bool) *connPLC {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen err: %v", err)
+	}
+	plc := &connPLC{Listener: l}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&plc.accepts, 1)
+			go func() {
+				defer conn.Close()
+				buff := make([]byte, UDP_READ_BUFFER_SIZE)
+				for {
+					if _, err := conn.Read(buff); err != nil {
+						return
+					}
+					if _, err := conn.Write(resp); err != nil {
+						return
+					}
+					if closeIdle {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return plc
+}
+
+// healthCheckResponse is binary 3E frame response of loopback test.
+var healthCheckResponse = []byte{0xD0, 0x00, 0x00, 0xFF, 0xFF, 0x03, 0x00, 0x09, 0x00, 0x00, 0x00, 0x05, 0x00, 0x41, 0x42, 0x43, 0x44, 0x45}
+
+// eventRecorder records connection events.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []ConnEvent
+}
+
+func (r *eventRecorder) handle(event ConnEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) states() []ConnState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	states := make([]ConnState, 0, len(r.events))
+	for _, e := range r.events {
+		states = append(states, e.State)
+	}
+	return states
+}
+
+func TestClient3E_PersistentConnection(t *testing.T) {
+	plc := startConnPLC(t, healthCheckResponse, false)
+	defer plc.Close()
+
+	recorder := &eventRecorder{}
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation(), WithConnEventHandler(recorder.handle))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	if client.ConnState() != ConnDisconnected {
+		t.Fatalf("expected %v but actual is %v", ConnDisconnected, client.ConnState())
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := client.HealthCheck(); err != nil {
+			t.Fatalf("unexpected health check err: %v", err)
+		}
+	}
+	if accepts := atomic.LoadInt32(&plc.accepts); accepts != 1 {
+		t.Fatalf("expected %v but actual is %v", 1, accepts)
+	}
+	if client.ConnState() != ConnConnected {
+		t.Fatalf("expected %v but actual is %v", ConnConnected, client.ConnState())
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("unexpected close err: %v", err)
+	}
+	if err := client.HealthCheck(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected %v but actual is %v", ErrClosed, err)
+	}
+	if diff := cmp.Diff([]ConnState{ConnConnecting, ConnConnected, ConnDisconnected}, recorder.states()); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+}
+
+func TestClient3E_Reconnect(t *testing.T) {
+	// plc closes connection after each response
+	plc := startConnPLC(t, healthCheckResponse, true)
+	defer plc.Close()
+
+	recorder := &eventRecorder{}
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation(), WithConnEventHandler(recorder.handle))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	defer client.Close()
+
+	// closed connection is detected, and request is sent again on new connection
+	for i := 0; i < 3; i++ {
+		if err := client.HealthCheck(); err != nil {
+			t.Fatalf("unexpected health check err: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond) // wait close by plc
+	}
+	if accepts := atomic.LoadInt32(&plc.accepts); accepts != 3 {
+		t.Fatalf("expected %v but actual is %v", 3, accepts)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	var reconnects int
+	for _, e := range recorder.events {
+		if e.State == ConnConnected && e.Reconnect {
+			reconnects++
+		}
+		if e.State == ConnDisconnected && e.Err == nil {
+			t.Errorf("expected cause of disconnect but actual is nil")
+		}
+	}
+	if reconnects != 2 {
+		t.Fatalf("expected %v but actual is %v", 2, reconnects)
+	}
+}
+
+func TestClient3E_StaleConnectionWrite(t *testing.T) {
+	// plc closes connection after each response
+	plc := startConnPLC(t, healthCheckResponse, true)
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	defer client.Close()
+	tr := client.(*client3E).tr
+
+	if err := client.HealthCheck(); err != nil {
+		t.Fatalf("unexpected health check err: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond) // wait close by plc
+
+	// health check is sent again on new connection
+	if err := client.HealthCheck(); err != nil {
+		t.Fatalf("unexpected health check err: %v", err)
+	}
+	if tr.generation() != 2 {
+		t.Fatalf("expected %v but actual is %v", 2, tr.generation())
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// write may be executed by plc before closing the connection, so it is not sent again
+	if _, err := client.Write("D", 100, 1, []byte{0x01, 0x00}); err == nil {
+		t.Fatalf("expected error of closed connection but actual is nil")
+	}
+	if accepts := atomic.LoadInt32(&plc.accepts); accepts != 2 {
+		t.Fatalf("expected %v but actual is %v", 2, accepts)
+	}
+}
+
+func TestClient3E_ConnEventHandlerRequest(t *testing.T) {
+	plc := startConnPLC(t, healthCheckResponse, false)
+	defer plc.Close()
+
+	// handler sends request on ConnConnected while the request that dialed has the only in-flight slot
+	var client Client3E
+	handlerErr := make(chan error, 1)
+	handler := func(event ConnEvent) {
+		if event.State == ConnConnected {
+			handlerErr <- client.HealthCheck()
+		}
+	}
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation(), WithConnEventHandler(handler))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	defer client.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.HealthCheck()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected health check err: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("request that dialed is blocked by handler")
+	}
+	select {
+	case err := <-handlerErr:
+		if err != nil {
+			t.Fatalf("unexpected health check err in handler: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("request in handler is blocked")
+	}
+}
+
+func TestClient3E_ReconnectBackoff(t *testing.T) {
+	// closed port
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen err: %v", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	recorder := &eventRecorder{}
+	client, err := New3EClient("127.0.0.1", port, NewLocalStation(),
+		WithReconnectBackoff(100*time.Millisecond, time.Second), WithConnEventHandler(recorder.handle))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.HealthCheck(); err == nil {
+		t.Fatalf("expected dial error but actual is nil")
+	}
+
+	// next dial waits backoff
+	start := time.Now()
+	if err := client.HealthCheck(); err == nil {
+		t.Fatalf("expected dial error but actual is nil")
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("expected backoff %v but actual is %v", 100*time.Millisecond, elapsed)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	last := recorder.events[len(recorder.events)-1]
+	if last.State != ConnDisconnected || last.Failures != 2 || last.Err == nil {
+		t.Fatalf("expected 2 dial failures but actual is %+v", last)
+	}
+}
+
+func TestPersistentTransport_Backoff(t *testing.T) {
+	tr := &persistentTransport{minBackoff: 100 * time.Millisecond, maxBackoff: time.Second}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, v := range expected {
+		tr.failures = i + 1
+		if actual := tr.backoff(); actual != v {
+			t.Errorf("failures %v: expected %v but actual is %v", tr.failures, v, actual)
+		}
+	}
+}
+
+// pipelinePLC is fake plc that responds binary 4E frame word read with offset of the device as value.
+// It reads requests without waiting responses, and records maximum number of requests waiting response.
+type pipelinePLC struct {
+	net.Listener
+	// number of accepted connections
+	accepts int32
+	// number of requests waiting response, and its maximum
+	outstanding, maxOutstanding int32
+}
+
+func startPipelinePLC(t *testing.T) *pipelinePLC {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen err: %v", err)
+	}
+	plc := &pipelinePLC{Listener: l}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&plc.accepts, 1)
+
+			requests := make(chan []byte, 100)
+			go func() {
+				defer close(requests)
+				for {
+					// header until request data length, and then request data
+					header := make([]byte, 13)
+					if _, err := io.ReadFull(conn, header); err != nil {
+						return
+					}
+					req := make([]byte, 13+int(header[11])|int(header[12])<<8)
+					copy(req, header)
+					if _, err := io.ReadFull(conn, req[13:]); err != nil {
+						return
+					}
+					n := atomic.AddInt32(&plc.outstanding, 1)
+					for {
+						max := atomic.LoadInt32(&plc.maxOutstanding)
+						if n <= max || atomic.CompareAndSwapInt32(&plc.maxOutstanding, max, n) {
+							break
+						}
+					}
+					requests <- req
+				}
+			}()
+			go func() {
+				defer conn.Close()
+				for req := range requests {
+					time.Sleep(time.Millisecond)
+					offset := uint16(req[19]) | uint16(req[20])<<8
+					resp := []byte{0xD4, 0x00, req[2], req[3], 0x00, 0x00, 0x00, 0xFF, 0xFF, 0x03, 0x00, 0x04, 0x00, 0x00, 0x00, byte(offset), byte(offset >> 8)}
+					atomic.AddInt32(&plc.outstanding, -1)
+					if _, err := conn.Write(resp); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return plc
+}
+
+func TestClient4E_ConcurrentPipelining(t *testing.T) {
+	plc := startPipelinePLC(t)
+	defer plc.Close()
+
+	client, err := New4EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation(), WithMaxInFlight(4))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				offset := int64(g*100 + i)
+				words, err := client.ReadWords("D", offset, 1)
+				if err != nil {
+					t.Errorf("unexpected read err: %v", err)
+					return
+				}
+				if words[0] != uint16(offset) {
+					t.Errorf("expected %v but actual is %v", offset, words[0])
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if accepts := atomic.LoadInt32(&plc.accepts); accepts != 1 {
+		t.Fatalf("expected %v but actual is %v", 1, accepts)
+	}
+	if max := atomic.LoadInt32(&plc.maxOutstanding); max < 2 || max > 4 {
+		t.Fatalf("expected in-flight requests are 2 to 4 but actual is %v", max)
+	}
+}
+
+func TestClient3E_Concurrent(t *testing.T) {
+	plc := startMemoryPLC(t)
+	defer plc.Close()
+
+	// 3E frame sends next request after response even if max in-flight is larger
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation(), WithMaxInFlight(4))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			ctx := context.Background()
+			if g%2 == 0 {
+				ctx = ContextWithPriority(ctx, PriorityHigh)
+			}
+			for i := 0; i < 10; i++ {
+				offset := int64(g * 10)
+				data := []byte{byte(g), byte(i)}
+				if _, err := client.WriteContext(ctx, "D", offset, 1, data); err != nil {
+					t.Errorf("unexpected write err: %v", err)
+					return
+				}
+				words, err := client.ReadWords("D", offset, 1)
+				if err != nil {
+					t.Errorf("unexpected read err: %v", err)
+					return
+				}
+				if expected := uint16(g) | uint16(i)<<8; words[0] != expected {
+					t.Errorf("expected %v but actual is %v", expected, words[0])
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if plc.requests() != 160 {
+		t.Fatalf("expected %v but actual is %v", 160, plc.requests())
+	}
+}
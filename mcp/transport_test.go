@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+// startUDPPLC starts udp server that returns resp for each request datagram.
+func startUDPPLC(t *testing.T, resp []byte) *net.UDPConn {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("unexpected listen err: %v", err)
+	}
+
+	go func() {
+		buff := make([]byte, UDP_READ_BUFFER_SIZE)
+		for {
+			_, from, err := conn.ReadFromUDP(buff)
+			if err != nil {
+				return
+			}
+			if resp != nil {
+				_, _ = conn.WriteToUDP(resp, from)
+			}
+		}
+	}()
+	return conn
+}
+
+func TestClient3E_UDP(t *testing.T) {
+	resp, _ := hex.DecodeString("d00000ffff0300090000000500" + "4142434445")
+	plc := startUDPPLC(t, resp)
+	defer plc.Close()
+
+	addr := plc.LocalAddr().(*net.UDPAddr)
+	client, err := New3EClient("127.0.0.1", addr.Port, NewLocalStation(), WithUDP(time.Second))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	if err := client.HealthCheck(); err != nil {
+		t.Fatalf("unexpected error occured %v", err)
+	}
+}
+
+func TestClient3E_UDPTimeout(t *testing.T) {
+	plc := startUDPPLC(t, nil)
+	defer plc.Close()
+
+	addr := plc.LocalAddr().(*net.UDPAddr)
+	client, err := New3EClient("127.0.0.1", addr.Port, NewLocalStation(), WithUDP(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	if err := client.HealthCheck(); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected %v but actual is %v", ErrTimeout, err)
+	}
+}
+
+func TestClient3E_UDPPacketLost(t *testing.T) {
+	// response data length is 9 but actual is 7
+	resp, _ := hex.DecodeString("d00000ffff0300090000000500" + "41424344")
+	plc := startUDPPLC(t, resp)
+	defer plc.Close()
+
+	addr := plc.LocalAddr().(*net.UDPAddr)
+	client, err := New3EClient("127.0.0.1", addr.Port, NewLocalStation(), WithUDP(time.Second))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	if err := client.HealthCheck(); !errors.Is(err, ErrPacketLost) {
+		t.Fatalf("expected %v but actual is %v", ErrPacketLost, err)
+	}
+}
+
+func TestUDPTransport_IgnoreOtherPeer(t *testing.T) {
+	plc, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("unexpected listen err: %v", err)
+	}
+	defer plc.Close()
+
+	other, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("unexpected listen err: %v", err)
+	}
+	defer other.Close()
+
+	// datagram is response frame as it is
+	readAll := func(r io.Reader, maxSize int64) ([]byte, error) {
+		return ioutil.ReadAll(r)
+	}
+	tr := &udpTransport{udpAddr: plc.LocalAddr().(*net.UDPAddr), timeout: time.Second, readFrame: readAll}
+
+	// other peer sends datagram to client before plc responds
+	go func() {
+		buff := make([]byte, 16)
+		_, from, err := plc.ReadFromUDP(buff)
+		if err != nil {
+			return
+		}
+		_, _ = other.WriteToUDP([]byte("other"), from)
+		_, _ = plc.WriteToUDP([]byte("plc"), from)
+	}()
+
+	resp, err := tr.roundTrip(context.Background(), []byte("request"), 16)
+	if err != nil {
+		t.Fatalf("unexpected round trip err: %v", err)
+	}
+	if string(resp) != "plc" {
+		t.Fatalf("expected %v but actual is %v", "plc", string(resp))
+	}
+}
+
+// startTCPPLC starts tcp server that returns handler result for each request.
+func startTCPPLC(t *testing.T, handler func(req []byte) []byte) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen err: %v", err)
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buff := make([]byte, UDP_READ_BUFFER_SIZE)
+				for {
+					n, err := conn.Read(buff)
+					if err != nil {
+						return
+					}
+					if _, err := conn.Write(handler(buff[:n])); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return l
+}
+
+func TestResponseTimeout(t *testing.T) {
+	if actual := responseTimeout(MONITORING_TIMER); actual != 4*time.Second+RESPONSE_TIMEOUT_MARGIN {
+		t.Errorf("expected %v but actual is %v", 4*time.Second+RESPONSE_TIMEOUT_MARGIN, actual)
+	}
+	if actual := responseTimeout(MONITORING_TIMER_1E); actual != 3*time.Second+RESPONSE_TIMEOUT_MARGIN {
+		t.Errorf("expected %v but actual is %v", 3*time.Second+RESPONSE_TIMEOUT_MARGIN, actual)
+	}
+	// 0 is infinite wait
+	if actual := responseTimeout("0000"); actual != 0 {
+		t.Errorf("expected %v but actual is %v", 0, actual)
+	}
+}
+
+func TestClient3E_Context(t *testing.T) {
+	// plc accepts connection but never responds
+	tcpPLC := startSegmentPLC(t, nil, false)
+	defer tcpPLC.Close()
+	udpPLC := startUDPPLC(t, nil)
+	defer udpPLC.Close()
+
+	tcpClient, err := New3EClient("127.0.0.1", tcpPLC.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	udpClient, err := New3EClient("127.0.0.1", udpPLC.LocalAddr().(*net.UDPAddr).Port, NewLocalStation(), WithUDP(10*time.Second))
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+
+	for name, client := range map[string]Client3E{"tcp": tcpClient, "udp": udpClient} {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		start := time.Now()
+		if _, err := client.ReadContext(ctx, "D", 100, 3); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%v: expected %v but actual is %v", name, context.DeadlineExceeded, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("%v: deadline is not respected: %v", name, elapsed)
+		}
+		cancel()
+
+		ctx, cancel = context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+		if err := client.HealthCheckContext(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("%v: expected %v but actual is %v", name, context.Canceled, err)
+		}
+
+		// canceled context is not sent
+		if _, err := client.WriteContext(ctx, "D", 100, 1, []byte{0x01, 0x00}); !errors.Is(err, context.Canceled) {
+			t.Errorf("%v: expected %v but actual is %v", name, context.Canceled, err)
+		}
+
+		ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+		if _, err := client.BitWriteContext(ctx, "M", 10, []bool{true}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%v: expected %v but actual is %v", name, context.DeadlineExceeded, err)
+		}
+		cancel()
+
+		monitor, err := client.RegisterMonitor([]Device{{Name: "D", Offset: 100}}, nil)
+		if err != nil {
+			t.Fatalf("%v: unexpected register err: %v", name, err)
+		}
+		ctx, cancel = context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+		if _, _, err := monitor.ReadContext(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("%v: expected %v but actual is %v", name, context.Canceled, err)
+		}
+	}
+}
+
+func TestTCPTransport_Timeout(t *testing.T) {
+	plc := startSegmentPLC(t, nil, false)
+	defer plc.Close()
+
+	tr := &tcpTransport{tcpAddr: plc.Addr().(*net.TCPAddr), timeout: 100 * time.Millisecond, readFrame: frameReader3E(Binary, false)}
+	if _, err := tr.roundTrip(context.Background(), []byte("request"), 22); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected %v but actual is %v", ErrTimeout, err)
+	}
+}
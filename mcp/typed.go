@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// WordReadWriter reads and writes devices by word units and bit units. Client implements it.
+type WordReadWriter interface {
+	ReadWords(deviceName string, offset, numPoints int64) ([]uint16, error)
+	WriteWords(deviceName string, offset int64, values []uint16) error
+	ReadBits(deviceName string, offset, numPoints int64) ([]bool, error)
+	WriteBits(deviceName string, offset int64, values []bool) error
+}
+
+// deviceParser parses device address by numbering of the plc. WordReadWriter may implement it.
+type deviceParser interface {
+	parseDevice(address string) (Device, error)
+}
+
+// wordPointsLimiter returns maximum number of words of one read. WordReadWriter may implement it.
+type wordPointsLimiter interface {
+	maxWordPoints() int64
+}
+
+// Typed provides typed value accessors and struct binding on word units read and write of WordReadWriter like Client.
+// word is little endian, and double word and 4 words value are stored from low word like D100(low), D101(high).
+type Typed struct {
+	rw WordReadWriter
+}
+
+// NewTyped returns Typed that reads and writes devices by rw.
+func NewTyped(rw WordReadWriter) Typed {
+	return Typed{rw: rw}
+}
+
+// ReadUint16s reads n uint16 values from offset. 1 value is 1 word.
+func (t Typed) ReadUint16s(deviceName string, offset, n int64) ([]uint16, error) {
+	values, err := t.readUints(deviceName, offset, n, 1)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]uint16, len(values))
+	for i, v := range values {
+		results[i] = uint16(v)
+	}
+	return results, nil
+}
+
+// ReadInt16s reads n int16 values from offset. 1 value is 1 word.
+func (t Typed) ReadInt16s(deviceName string, offset, n int64) ([]int16, error) {
+	values, err := t.readUints(deviceName, offset, n, 1)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]int16, len(values))
+	for i, v := range values {
+		results[i] = int16(v)
+	}
+	return results, nil
+}
+
+// ReadUint32s reads n uint32 values from offset. 1 value is 2 words.
+func (t Typed) ReadUint32s(deviceName string, offset, n int64) ([]uint32, error) {
+	values, err := t.readUints(deviceName, offset, n, 2)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]uint32, len(values))
+	for i, v := range values {
+		results[i] = uint32(v)
+	}
+	return results, nil
+}
+
+// ReadInt32s reads n int32 values from offset. 1 value is 2 words.
+func (t Typed) ReadInt32s(deviceName string, offset, n int64) ([]int32, error) {
+	values, err := t.readUints(deviceName, offset, n, 2)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]int32, len(values))
+	for i, v := range values {
+		results[i] = int32(v)
+	}
+	return results, nil
+}
+
+// ReadFloat32s reads n float32 (single precision real number) values from offset. 1 value is 2 words.
+func (t Typed) ReadFloat32s(deviceName string, offset, n int64) ([]float32, error) {
+	values, err := t.readUints(deviceName, offset, n, 2)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]float32, len(values))
+	for i, v := range values {
+		results[i] = math.Float32frombits(uint32(v))
+	}
+	return results, nil
+}
+
+// ReadFloat64s reads n float64 (double precision real number) values from offset. 1 value is 4 words.
+func (t Typed) ReadFloat64s(deviceName string, offset, n int64) ([]float64, error) {
+	values, err := t.readUints(deviceName, offset, n, 4)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]float64, len(values))
+	for i, v := range values {
+		results[i] = math.Float64frombits(v)
+	}
+	return results, nil
+}
+
+// ReadString reads string of numPoints words from offset. 1 word is 2 characters stored from lower byte.
+// string is terminated at the first NUL character.
+func (t Typed) ReadString(deviceName string, offset, numPoints int64) (string, error) {
+	words, err := t.ReadUint16s(deviceName, offset, numPoints)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, 0, 2*len(words))
+	for _, w := range words {
+		b = append(b, byte(w), byte(w>>8))
+	}
+	s := string(b)
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		s = s[:i]
+	}
+	return s, nil
+}
+
+// WriteUint16s writes uint16 values from offset. 1 value is 1 word.
+func (t Typed) WriteUint16s(deviceName string, offset int64, values []uint16) error {
+	return t.rw.WriteWords(deviceName, offset, values)
+}
+
+// WriteInt16s writes int16 values from offset. 1 value is 1 word.
+func (t Typed) WriteInt16s(deviceName string, offset int64, values []int16) error {
+	uints := make([]uint64, len(values))
+	for i, v := range values {
+		uints[i] = uint64(uint16(v))
+	}
+	return t.writeUints(deviceName, offset, uints, 1)
+}
+
+// WriteUint32s writes uint32 values from offset. 1 value is 2 words.
+func (t Typed) WriteUint32s(deviceName string, offset int64, values []uint32) error {
+	uints := make([]uint64, len(values))
+	for i, v := range values {
+		uints[i] = uint64(v)
+	}
+	return t.writeUints(deviceName, offset, uints, 2)
+}
+
+// WriteInt32s writes int32 values from offset. 1 value is 2 words.
+func (t Typed) WriteInt32s(deviceName string, offset int64, values []int32) error {
+	uints := make([]uint64, len(values))
+	for i, v := range values {
+		uints[i] = uint64(uint32(v))
+	}
+	return t.writeUints(deviceName, offset, uints, 2)
+}
+
+// WriteFloat32s writes float32 (single precision real number) values from offset. 1 value is 2 words.
+func (t Typed) WriteFloat32s(deviceName string, offset int64, values []float32) error {
+	uints := make([]uint64, len(values))
+	for i, v := range values {
+		uints[i] = uint64(math.Float32bits(v))
+	}
+	return t.writeUints(deviceName, offset, uints, 2)
+}
+
+// WriteFloat64s writes float64 (double precision real number) values from offset. 1 value is 4 words.
+func (t Typed) WriteFloat64s(deviceName string, offset int64, values []float64) error {
+	uints := make([]uint64, len(values))
+	for i, v := range values {
+		uints[i] = math.Float64bits(v)
+	}
+	return t.writeUints(deviceName, offset, uints, 4)
+}
+
+// WriteString writes s to numPoints words from offset. 1 word is 2 characters stored from lower byte.
+// rest of words is padded with NUL. s must be less than or equal to 2*numPoints bytes.
+func (t Typed) WriteString(deviceName string, offset, numPoints int64, s string) error {
+	if int64(len(s)) > 2*numPoints {
+		return fmt.Errorf("string must be less than or equal to %v byte but actual is %v byte", 2*numPoints, len(s))
+	}
+	b := make([]byte, 2*numPoints)
+	copy(b, s)
+
+	words := make([]uint16, numPoints)
+	for i := range words {
+		words[i] = uint16(b[2*i]) | uint16(b[2*i+1])<<8
+	}
+	return t.rw.WriteWords(deviceName, offset, words)
+}
+
+// readUints reads n values of size words from offset. value is stored from low word.
+func (t Typed) readUints(deviceName string, offset, n int64, size int) ([]uint64, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("number of values must be larger than 0 but actual is %v", n)
+	}
+	words, err := t.rw.ReadWords(deviceName, offset, n*int64(size))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(words)) != n*int64(size) {
+		return nil, fmt.Errorf("%v words are read but actual is %v words", n*int64(size), len(words))
+	}
+
+	values := make([]uint64, n)
+	for i := range values {
+		for j := size - 1; j >= 0; j-- {
+			values[i] = values[i]<<16 | uint64(words[i*size+j])
+		}
+	}
+	return values, nil
+}
+
+// writeUints writes values of size words from offset. value is stored from low word.
+func (t Typed) writeUints(deviceName string, offset int64, values []uint64, size int) error {
+	if len(values) == 0 {
+		return fmt.Errorf("number of values must be larger than 0 but actual is %v", len(values))
+	}
+
+	words := make([]uint16, 0, len(values)*size)
+	for _, v := range values {
+		for j := 0; j < size; j++ {
+			words = append(words, uint16(v>>(16*j)))
+		}
+	}
+	return t.rw.WriteWords(deviceName, offset, words)
+}
+
+// parseDevice parses device address by numbering of rw, or by ParseDevice if rw does not know numbering.
+func (t Typed) parseDevice(address string) (Device, error) {
+	if p, ok := t.rw.(deviceParser); ok {
+		return p.parseDevice(address)
+	}
+	return ParseDevice(address)
+}
+
+// maxWordPoints returns maximum number of words of one read of rw, or of Q series if rw does not know it.
+func (t Typed) maxWordPoints() int64 {
+	if l, ok := t.rw.(wordPointsLimiter); ok {
+		return l.maxWordPoints()
+	}
+	return ProfileOf(SeriesQ).MaxWordPoints
+}
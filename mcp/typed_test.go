@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTyped(t *testing.T) {
+	plc := startMemoryPLC(t)
+	defer plc.Close()
+
+	client, err := New3EClient("127.0.0.1", plc.Addr().(*net.TCPAddr).Port, NewLocalStation())
+	if err != nil {
+		t.Fatalf("unexpected client err: %v", err)
+	}
+	typed := NewTyped(client)
+
+	// double word is stored from low word
+	if err := typed.WriteInt32s("D", 100, []int32{-2, 0x12345678}); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+	words, err := typed.ReadUint16s("D", 100, 4)
+	if err != nil {
+		t.Fatalf("unexpected read err: %v", err)
+	}
+	if diff := cmp.Diff([]uint16{0xFFFE, 0xFFFF, 0x5678, 0x1234}, words); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+	int32s, err := typed.ReadInt32s("D", 100, 2)
+	if err != nil {
+		t.Fatalf("unexpected read err: %v", err)
+	}
+	if diff := cmp.Diff([]int32{-2, 0x12345678}, int32s); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+
+	if err := typed.WriteInt16s("D", 200, []int16{-1, 1}); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+	int16s, _ := typed.ReadInt16s("D", 200, 2)
+	if diff := cmp.Diff([]int16{-1, 1}, int16s); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+
+	if err := typed.WriteFloat32s("D", 300, []float32{1.5, -0.25}); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+	float32s, _ := typed.ReadFloat32s("D", 300, 2)
+	if diff := cmp.Diff([]float32{1.5, -0.25}, float32s); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+
+	if err := typed.WriteFloat64s("D", 400, []float64{3.14159}); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+	float64s, _ := typed.ReadFloat64s("D", 400, 1)
+	if diff := cmp.Diff([]float64{3.14159}, float64s); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+
+	// 1 word is 2 characters from lower byte
+	if err := typed.WriteString("D", 500, 4, "ABC"); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+	chars, _ := typed.ReadUint16s("D", 500, 2)
+	if diff := cmp.Diff([]uint16{0x4241, 0x0043}, chars); diff != "" {
+		t.Fatalf("(-expected +actual)\n%s", diff)
+	}
+	s, _ := typed.ReadString("D", 500, 4)
+	if s != "ABC" {
+		t.Fatalf("expected %v but actual is %v", "ABC", s)
+	}
+
+	if err := typed.WriteString("D", 500, 1, "ABC"); err == nil {
+		t.Fatalf("expected error of too long string but actual is nil")
+	}
+	if _, err := typed.ReadInt32s("D", 100, 0); err == nil {
+		t.Fatalf("expected error of 0 values but actual is nil")
+	}
+}
+
+// memoryWords is WordReadWriter on memory for Typed without plc.
+type memoryWords map[string]uint16
+
+func (m memoryWords) ReadWords(deviceName string, offset, numPoints int64) ([]uint16, error) {
+	words := make([]uint16, numPoints)
+	for i := range words {
+		words[i] = m[fmt.Sprintf("%v%v", deviceName, offset+int64(i))]
+	}
+	return words, nil
+}
+
+func (m memoryWords) WriteWords(deviceName string, offset int64, values []uint16) error {
+	for i, v := range values {
+		m[fmt.Sprintf("%v%v", deviceName, offset+int64(i))] = v
+	}
+	return nil
+}
+
+func (m memoryWords) ReadBits(deviceName string, offset, numPoints int64) ([]bool, error) {
+	return nil, errors.New("bit is not supported")
+}
+
+func (m memoryWords) WriteBits(deviceName string, offset int64, values []bool) error {
+	return errors.New("bit is not supported")
+}
+
+func TestTyped_WordReadWriter(t *testing.T) {
+	m := memoryWords{}
+	typed := NewTyped(m)
+
+	if err := typed.WriteUint32s("D", 10, []uint32{0x00010002}); err != nil {
+		t.Fatalf("unexpected write err: %v", err)
+	}
+	if diff := cmp.Diff(m, memoryWords{"D10": 0x0002, "D11": 0x0001}); diff != "" {
+		t.Errorf("words differs: (-got +want)\n%s", diff)
+	}
+
+	var v struct {
+		A uint32 `mcp:"D10"`
+		B int16  `mcp:"D12"`
+	}
+	m["D12"] = 0xFFFF
+	if err := typed.ReadStruct(&v); err != nil {
+		t.Fatalf("unexpected read err: %v", err)
+	}
+	if v.A != 0x00010002 || v.B != -1 {
+		t.Fatalf("expected %v, %v but actual is %v, %v", 0x00010002, -1, v.A, v.B)
+	}
+}
@@ -22,6 +22,8 @@ type fileMirror struct {
 	offset     int64
 	numPoints  int64
 	interval   time.Duration
+	// monitor of devices. if it is nil, devices are read by batch read
+	monitor *mcp.Monitor
 }
 
 func NewFileMirror(c mcp.Client, w io.Writer, deviceName string, offset, numPoints int64, interval time.Duration) *fileMirror {
@@ -35,6 +37,24 @@ func NewFileMirror(c mcp.Client, w io.Writer, deviceName string, offset, numPoin
 	}
 }
 
+// NewFileMonitorMirror returns mirror that reads devices by monitor command, which is cheaper for plc cpu than batch read.
+// devices are registered to plc once, and registered again after reconnection.
+// numPoints must be less than or equal to the limit of random read. each line has words of devices in little endian, not response frame.
+func NewFileMonitorMirror(c mcp.Client3E, w io.Writer, deviceName string, offset, numPoints int64, interval time.Duration) (*fileMirror, error) {
+	words := make([]mcp.Device, 0, numPoints)
+	for i := int64(0); i < numPoints; i++ {
+		words = append(words, mcp.Device{Name: deviceName, Offset: offset + i})
+	}
+	monitor, err := c.RegisterMonitor(words, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	m := NewFileMirror(c, w, deviceName, offset, numPoints, interval)
+	m.monitor = monitor
+	return m, nil
+}
+
 func (m *fileMirror) RunAndServe() error {
 	c := time.Tick(m.interval)
 	for {
@@ -54,7 +74,7 @@ func (m *fileMirror) readAndWrite() {
 	}
 	defer m.Unlock()
 
-	bytes, err := m.c.Read(m.deviceName, m.offset, m.numPoints)
+	bytes, err := m.read()
 	if err != nil {
 		log.Printf("[ERROR] plc read error: %v\n", err)
 		return
@@ -69,6 +89,23 @@ func (m *fileMirror) readAndWrite() {
 
 }
 
+// read returns response of batch read, or words of monitor in little endian
+func (m *fileMirror) read() ([]byte, error) {
+	if m.monitor == nil {
+		return m.c.Read(m.deviceName, m.offset, m.numPoints)
+	}
+
+	words, _, err := m.monitor.Read()
+	if err != nil {
+		return nil, err
+	}
+	bytes := make([]byte, 0, 2*len(words))
+	for _, v := range words {
+		bytes = append(bytes, byte(v), byte(v>>8))
+	}
+	return bytes, nil
+}
+
 // Guards duplicate plc access and skip when delay read operation for reducing plc workload
 func (m *fileMirror) Lock() bool {
 	mu.Lock()